@@ -28,6 +28,7 @@ import (
 	"go.uber.org/goleak"
 
 	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+	"github.com/conduitio-labs/conduit-connector-firebolt/client/fireboltest"
 	"github.com/conduitio-labs/conduit-connector-firebolt/config"
 )
 
@@ -49,9 +50,8 @@ func (d *driver) GenerateRecord(_ *testing.T, op sdk.Operation) sdk.Record {
 	return sdk.Record{
 		Operation: op,
 		Key: sdk.StructuredData{
-			// convert to float64, since the connector will unmarhsal the value into "any" as float64
-			// see https://pkg.go.dev/encoding/json#Unmarshal
-			"id": float64(d.counter),
+			// the source converts the values of INT columns to int64.
+			"id": d.counter,
 		},
 		Payload: sdk.Change{After: sdk.RawData(
 			fmt.Sprintf(
@@ -72,11 +72,6 @@ func TestAcceptance(t *testing.T) {
 				SourceConfig:      cfg,
 				DestinationConfig: cfg,
 				BeforeTest:        beforeTest(cfg),
-				Skip: []string{
-					// Firebolt doesn't have cdc iterator
-					"TestSource_Open_ResumeAtPositionCDC",
-					"TestSource_Read_Success",
-				},
 				GoleakOptions: []goleak.Option{
 					// the problem with leak goroutines is related to
 					// KeepAlive and TLSHandshake timeouts in go-retryablehttp's Dialer.
@@ -104,10 +99,6 @@ func prepareConfig(t *testing.T) map[string]string {
 	engineName := os.Getenv("FIREBOLT_ENGINE_NAME")
 	db := os.Getenv("FIREBOLT_DB")
 
-	if email == "" || password == "" || accountName == "" || engineName == "" || db == "" {
-		t.Skip("missed env variable")
-	}
-
 	cfg := map[string]string{
 		config.KeyEmail:           email,
 		config.KeyPassword:        password,
@@ -115,22 +106,39 @@ func prepareConfig(t *testing.T) map[string]string {
 		config.KeyEngineName:      engineName,
 		config.KeyDB:              db,
 		config.KeyOrderingColumns: "id",
+		config.KeyTrackingColumn:  "id",
 		config.KeyBatchSize:       "100",
 	}
 
+	// run the suite against the fake server if a real Firebolt account is not configured.
+	if email == "" || password == "" || accountName == "" || engineName == "" || db == "" {
+		srv := fireboltest.NewServer()
+		t.Cleanup(srv.Close)
+
+		cfg[config.KeyEmail] = fireboltest.Email
+		cfg[config.KeyPassword] = fireboltest.Password
+		cfg[config.KeyAccountName] = fireboltest.AccountName
+		cfg[config.KeyEngineName] = fireboltest.EngineName
+		cfg[config.KeyDB] = fireboltest.DB
+		cfg[config.KeyAPIURL] = srv.URL
+	}
+
 	return cfg
 }
 
 func prepareData(ctx context.Context, t *testing.T, cfg map[string]string) error {
-	cl := client.New(ctx, cfg[config.KeyDB])
+	cl := client.New(ctx, cfg[config.KeyAPIURL], cfg[config.KeyDB])
 
 	err := cl.Login(ctx, client.LoginParams{
 		Email:       cfg[config.KeyEmail],
 		Password:    cfg[config.KeyPassword],
 		AccountName: cfg[config.KeyAccountName],
 		EngineName:  cfg[config.KeyEngineName],
+		EngineURL:   cfg[config.KeyEngineURL],
 	})
 	if err != nil {
+		cl.Close(ctx)
+
 		return fmt.Errorf("client login: %w", err)
 	}
 
@@ -141,9 +149,14 @@ func prepareData(ctx context.Context, t *testing.T, cfg map[string]string) error
 	// create table.
 	_, err = cl.RunQuery(ctx, queryCreateTable)
 	if err != nil {
+		cl.Close(ctx)
+
 		return err
 	}
 
+	// close idle connections, so they are not reported as leaked goroutines.
+	cl.Close(ctx)
+
 	cfg[config.KeyTable] = tableName
 
 	// drop table
@@ -154,6 +167,8 @@ func prepareData(ctx context.Context, t *testing.T, cfg map[string]string) error
 		if err != nil {
 			t.Errorf("drop test table: %v", err)
 		}
+
+		cl.Close(ctx)
 	})
 
 	return nil
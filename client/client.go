@@ -22,7 +22,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	sdk "github.com/conduitio/conduit-connector-sdk"
@@ -31,18 +35,34 @@ import (
 )
 
 const (
-	baseURL            = "https://api.app.firebolt.io"
-	loginURL           = baseURL + "/auth/v1/login"
-	refreshTokenURL    = baseURL + "/auth/v1/refresh"
-	accountIDByNameURL = baseURL + "/iam/v2/accounts:getIdByName?account_name=%s"
-	engineIDByNameURL  = baseURL + "/core/v1/accounts/%s/engines:getIdByName?engine_name=%s"
-	engineURLByNameURL = baseURL + "/core/v1/accounts/%s/engines?filter.name_contains=%s"
-	engineByIDURL      = baseURL + "/core/v1/accounts/%s/engines/%s"
-	startEngineURL     = baseURL + "/core/v1/accounts/%s/engines/%s:start"
+	// DefaultAPIURL is the base URL of the Firebolt API used when no other is configured.
+	DefaultAPIURL = "https://api.app.firebolt.io"
 
-	databaseURL = "https://%s/?database=%s"
+	authPathPrefix      = "/auth/"
+	loginPath           = "/auth/v1/login"
+	refreshTokenPath    = "/auth/v1/refresh"
+	serviceAccountPath  = "/auth/v1/token"
+	accountIDByNamePath = "/iam/v2/accounts:getIdByName?account_name=%s"
+	engineIDByNamePath  = "/core/v1/accounts/%s/engines:getIdByName?engine_name=%s"
+	engineURLByNamePath = "/core/v1/accounts/%s/engines?filter.name_contains=%s"
+	engineByIDPath      = "/core/v1/accounts/%s/engines/%s"
+	startEnginePath     = "/core/v1/accounts/%s/engines/%s:start"
+
+	databasePath = "/?database=%s"
+
+	// defaultEngineScheme is a scheme of engine endpoints, the Firebolt API returns them without it.
+	defaultEngineScheme = "https://"
 
 	queryShowIndexes = "SHOW INDEXES;"
+	queryShowTables  = "SHOW TABLES;"
+	// aggregateMin and aggregateMax are the aggregate functions of the column values.
+	aggregateMin = "MIN"
+	aggregateMax = "MAX"
+	// queryAlias is an alias of the subquery which result is read instead of a table.
+	queryAlias = "query"
+
+	// grantTypeClientCredentials is the OAuth grant type used by service accounts.
+	grantTypeClientCredentials = "client_credentials"
 
 	// retryMax is the maximum number of retries.
 	retryMax = 3
@@ -51,11 +71,20 @@ const (
 )
 
 var (
-	layouts = []string{"2006-01-02", "2006-01-02 15:04:05"}
+	// layouts are the layouts of dates and timestamps, the fractional seconds are parsed with any of them.
+	layouts = []string{
+		"2006-01-02",
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04:05Z07",
+		"2006-01-02 15:04:05Z07:00",
+		time.RFC3339Nano,
+	}
 )
 
 // Client for calls to firebolt.
 type Client struct {
+	// tokenMu guards the accessToken, it's refreshed by any of the concurrent requests.
+	tokenMu        sync.RWMutex
 	accessToken    string
 	refreshToken   string
 	accountID      string
@@ -64,13 +93,25 @@ type Client struct {
 	engineName     string
 	engineEndpoint string
 	dbName         string
+	clientID       string
+	clientSecret   string
+	apiURL         string
+	// engineURLOverridden is true when the engine endpoint is configured explicitly,
+	// in that case the client doesn't discover and manage the engine.
+	engineURLOverridden bool
 
 	httpClient *http.Client
 }
 
 // New creates new instance of the Client.
-func New(ctx context.Context, dbName string) *Client {
+// If apiURL is empty the client uses DefaultAPIURL.
+func New(ctx context.Context, apiURL, dbName string) *Client {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+
 	client := &Client{
+		apiURL: strings.TrimSuffix(apiURL, "/"),
 		dbName: dbName,
 	}
 
@@ -84,33 +125,45 @@ func New(ctx context.Context, dbName string) *Client {
 }
 
 // LoginParams is an incoming params for the Login method.
+// Either Email and Password or ClientID and ClientSecret must be set.
+// If EngineURL is set, AccountName and EngineName are not used.
 type LoginParams struct {
-	Email       string
-	Password    string
-	AccountName string
-	EngineName  string
+	Email        string
+	Password     string
+	ClientID     string
+	ClientSecret string
+	AccountName  string
+	EngineName   string
+	EngineURL    string
 }
 
 // Login logins to firebolt.
+// If the params contain a ClientID the client authenticates as a service account
+// using the OAuth client credentials flow, otherwise it uses the user's email and password.
 func (c *Client) Login(ctx context.Context, params LoginParams) error {
-	request := loginRequest{
-		Username: params.Email,
-		Password: params.Password,
+	var err error
+
+	if params.ClientID != "" {
+		c.clientID = params.ClientID
+		c.clientSecret = params.ClientSecret
+
+		err = c.loginServiceAccount(ctx)
+	} else {
+		err = c.loginUser(ctx, params.Email, params.Password)
 	}
 
-	req, err := c.newRequest(ctx, http.MethodPost, loginURL, &request)
 	if err != nil {
-		return fmt.Errorf("create login request: %w", err)
+		return err
 	}
 
-	var resp loginResponse
-	err = c.do(ctx, req, &resp)
-	if err != nil {
-		return fmt.Errorf("execute login request: %w", err)
+	// the engine endpoint is known, account and engine discovery is not needed.
+	if params.EngineURL != "" {
+		c.engineEndpoint = params.EngineURL
+		c.engineURLOverridden = true
+
+		return nil
 	}
 
-	c.accessToken = resp.AccessToken
-	c.refreshToken = resp.RefreshToken
 	c.accountName = params.AccountName
 	c.engineName = params.EngineName
 
@@ -134,12 +187,17 @@ func (c *Client) Login(ctx context.Context, params LoginParams) error {
 
 // StartEngine starts a Firebolt engine and returns
 // a bool indicating whether the engine is started or not.
+// If the engine endpoint is configured explicitly the engine is considered started.
 func (c *Client) StartEngine(ctx context.Context) (bool, error) {
+	if c.engineURLOverridden {
+		return true, nil
+	}
+
 	if c.accountID == "" || c.engineID == "" {
 		return false, errAccountIDOrEngineIDIsEmpty
 	}
 
-	req, err := c.newRequest(ctx, http.MethodPost, fmt.Sprintf(startEngineURL, c.accountID, c.engineID), nil)
+	req, err := c.newRequest(ctx, http.MethodPost, c.apiEndpoint(startEnginePath, c.accountID, c.engineID), nil)
 	if err != nil {
 		return false, fmt.Errorf("create start engine request: %w", err)
 	}
@@ -159,8 +217,8 @@ func (c *Client) StartEngine(ctx context.Context) (bool, error) {
 func (c *Client) RunQuery(ctx context.Context, query string) (*RunQueryResponse, error) {
 	b := bytes.NewBuffer([]byte(query))
 
-	req, err := c.newRequest(ctx, http.MethodPost, fmt.Sprintf(databaseURL,
-		c.engineEndpoint, c.dbName), b)
+	req, err := c.newRequest(ctx, http.MethodPost,
+		c.engineBaseURL()+fmt.Sprintf(databasePath, url.QueryEscape(c.dbName)), b)
 	if err != nil {
 		return nil, fmt.Errorf("create run query request: %w", err)
 	}
@@ -190,12 +248,17 @@ func (c *Client) GetEngineStatus(ctx context.Context) (string, error) {
 
 // RefreshToken performs a refresh token request.
 // The method set the *Client.accessToken field to the new access token.
+// Service accounts don't get a refresh token, so for them the method requests a new access token instead.
 func (c *Client) RefreshToken(ctx context.Context) error {
+	if c.clientID != "" {
+		return c.loginServiceAccount(ctx)
+	}
+
 	request := refreshTokenRequest{
 		RefreshToken: c.refreshToken,
 	}
 
-	req, err := c.newRequest(ctx, http.MethodPost, refreshTokenURL, &request)
+	req, err := c.newRequest(ctx, http.MethodPost, c.apiEndpoint(refreshTokenPath), &request)
 	if err != nil {
 		return fmt.Errorf("create refresh token request: %w", err)
 	}
@@ -206,7 +269,7 @@ func (c *Client) RefreshToken(ctx context.Context) error {
 		return fmt.Errorf("execute refresh token request: %w", err)
 	}
 
-	c.accessToken = loginResp.AccessToken
+	c.setAccessToken(loginResp.AccessToken)
 
 	return nil
 }
@@ -214,14 +277,20 @@ func (c *Client) RefreshToken(ctx context.Context) error {
 // WaitEngineStarted periodically checks the engine status,
 // and if the status is equal to ENGINE_STATUS_RUNNING_REVISION_SERVING or ctx is canceled returns.
 // It's a blocking method.
+// If the engine endpoint is configured explicitly the method returns immediately.
 func (c *Client) WaitEngineStarted(ctx context.Context) error {
+	if c.engineURLOverridden {
+		return nil
+	}
+
 	ticker := time.NewTicker(engineStatusCheckTimeout)
+	defer ticker.Stop()
 
 	if c.accountID == "" || c.engineID == "" {
 		return errAccountIDOrEngineIDIsEmpty
 	}
 
-	req, err := c.newRequest(ctx, http.MethodPost, fmt.Sprintf(startEngineURL, c.accountID, c.engineID), nil)
+	req, err := c.newRequest(ctx, http.MethodPost, c.apiEndpoint(startEnginePath, c.accountID, c.engineID), nil)
 	if err != nil {
 		return fmt.Errorf("create start engine request: %w", err)
 	}
@@ -261,7 +330,7 @@ func (c *Client) WaitEngineStarted(ctx context.Context) error {
 
 				isEngineStarted, er := c.StartEngine(ctx)
 				if er != nil {
-					return fmt.Errorf("start engine: %w", err)
+					return fmt.Errorf("start engine: %w", er)
 				}
 
 				if isEngineStarted {
@@ -280,11 +349,67 @@ func (c *Client) WaitEngineStarted(ctx context.Context) error {
 // Close closes the HTTP client connections.
 func (c *Client) Close(_ context.Context) {
 	c.httpClient.CloseIdleConnections()
+
+	// the retryable round tripper doesn't propagate closing to the client it wraps.
+	if rt, ok := c.httpClient.Transport.(*retryablehttp.RoundTripper); ok {
+		rt.Client.HTTPClient.CloseIdleConnections()
+	}
+}
+
+// loginUser authenticates the client using a user's email and password.
+func (c *Client) loginUser(ctx context.Context, email, password string) error {
+	request := loginRequest{
+		Username: email,
+		Password: password,
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, c.apiEndpoint(loginPath), &request)
+	if err != nil {
+		return fmt.Errorf("create login request: %w", err)
+	}
+
+	var resp loginResponse
+	err = c.do(ctx, req, &resp)
+	if err != nil {
+		return fmt.Errorf("execute login request: %w", err)
+	}
+
+	c.setAccessToken(resp.AccessToken)
+	c.refreshToken = resp.RefreshToken
+
+	return nil
+}
+
+// loginServiceAccount authenticates the client using the service account's client id and secret.
+func (c *Client) loginServiceAccount(ctx context.Context) error {
+	form := url.Values{
+		"grant_type":    {grantTypeClientCredentials},
+		"client_id":     {c.clientID},
+		"client_secret": {c.clientSecret},
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, c.apiEndpoint(serviceAccountPath),
+		bytes.NewBufferString(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("create service account login request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp loginResponse
+	err = c.do(ctx, req, &resp)
+	if err != nil {
+		return fmt.Errorf("execute service account login request: %w", err)
+	}
+
+	c.setAccessToken(resp.AccessToken)
+
+	return nil
 }
 
 // getAccountIDByName returns an account id by its name.
 func (c *Client) getAccountIDByName(ctx context.Context) (string, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf(accountIDByNameURL, c.accountName), nil)
+	req, err := c.newRequest(ctx, http.MethodGet, c.apiEndpoint(accountIDByNamePath, url.QueryEscape(c.accountName)), nil)
 	if err != nil {
 		return "", fmt.Errorf("create get account id request: %w", err)
 	}
@@ -300,7 +425,9 @@ func (c *Client) getAccountIDByName(ctx context.Context) (string, error) {
 
 // getEngineURLByName returns an engine URL by its name.
 func (c *Client) getEngineURLByName(ctx context.Context) (string, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf(engineURLByNameURL, c.accountID, c.engineName), nil)
+	endpoint := c.apiEndpoint(engineURLByNamePath, c.accountID, url.QueryEscape(c.engineName))
+
+	req, err := c.newRequest(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return "", fmt.Errorf("create get engine id request: %w", err)
 	}
@@ -321,7 +448,9 @@ func (c *Client) getEngineURLByName(ctx context.Context) (string, error) {
 
 // getEngineIDByName returns an engine id by its name.
 func (c *Client) getEngineIDByName(ctx context.Context) (string, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf(engineIDByNameURL, c.accountID, c.engineName), nil)
+	endpoint := c.apiEndpoint(engineIDByNamePath, c.accountID, url.QueryEscape(c.engineName))
+
+	req, err := c.newRequest(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return "", fmt.Errorf("create get engine id request: %w", err)
 	}
@@ -337,7 +466,7 @@ func (c *Client) getEngineIDByName(ctx context.Context) (string, error) {
 
 // getEngineByID returns engineResponse.
 func (c *Client) getEngineByID(ctx context.Context) (*engineResponse, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf(engineByIDURL, c.accountID, c.engineID), nil)
+	req, err := c.newRequest(ctx, http.MethodGet, c.apiEndpoint(engineByIDPath, c.accountID, c.engineID), nil)
 	if err != nil {
 		return nil, fmt.Errorf("create get engine id request: %w", err)
 	}
@@ -351,6 +480,37 @@ func (c *Client) getEngineByID(ctx context.Context) (*engineResponse, error) {
 	return &resp, nil
 }
 
+// getAccessToken returns the current access token.
+func (c *Client) getAccessToken() string {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+
+	return c.accessToken
+}
+
+// setAccessToken sets the access token used by the following requests.
+func (c *Client) setAccessToken(accessToken string) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	c.accessToken = accessToken
+}
+
+// apiEndpoint returns an absolute URL of the Firebolt API endpoint
+// built from the path format and its arguments.
+func (c *Client) apiEndpoint(format string, args ...any) string {
+	return c.apiURL + fmt.Sprintf(format, args...)
+}
+
+// engineBaseURL returns the engine endpoint prefixed with a scheme if it doesn't have one.
+func (c *Client) engineBaseURL() string {
+	if strings.Contains(c.engineEndpoint, "://") {
+		return strings.TrimSuffix(c.engineEndpoint, "/")
+	}
+
+	return defaultEngineScheme + strings.TrimSuffix(c.engineEndpoint, "/")
+}
+
 // NewRequest creates an API request.
 func (c *Client) newRequest(ctx context.Context, method, url string, body any) (*http.Request, error) {
 	var (
@@ -383,8 +543,8 @@ func (c *Client) newRequest(ctx context.Context, method, url string, body any) (
 		req.Header.Set("Content-Type", "application/json;charset=UTF-8")
 	}
 
-	if c.accessToken != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
+	if accessToken := c.getAccessToken(); accessToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
 	}
 
 	return req, nil
@@ -419,7 +579,11 @@ func (c *Client) do(_ context.Context, req *http.Request, out any) error {
 		}
 
 	default:
-		err = json.NewDecoder(resp.Body).Decode(out)
+		// numbers are decoded as json.Number, so the integers out of the float64 precision aren't corrupted.
+		decoder := json.NewDecoder(resp.Body)
+		decoder.UseNumber()
+
+		err = decoder.Decode(out)
 		if err != nil && !errors.Is(err, io.EOF) {
 			return fmt.Errorf("decode response body: %w", err)
 		}
@@ -457,13 +621,16 @@ func (c *Client) checkRetry(ctx context.Context, resp *http.Response, err error)
 		return true, respErr
 	}
 
-	if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+	// authentication requests are not retried with a refreshed token,
+	// it's their response that would be used to refresh it.
+	if resp != nil && resp.StatusCode == http.StatusUnauthorized &&
+		!strings.HasPrefix(resp.Request.URL.String(), c.apiEndpoint(authPathPrefix)) {
 		if err = c.RefreshToken(ctx); err != nil {
 			return true, fmt.Errorf("refresh token: %w", err)
 		}
 
 		// set Authorization header to the newly created access token
-		resp.Request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
+		resp.Request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.getAccessToken()))
 
 		// shouldRetry is true cause we need to retry one more time with the new access token.
 		return true, respErr
@@ -472,15 +639,101 @@ func (c *Client) checkRetry(ctx context.Context, resp *http.Response, err error)
 	return false, nil
 }
 
+// GetRowsParams is an incoming params for the GetRows method.
+type GetRowsParams struct {
+	Table string
+	// Query is a SELECT query which result is read instead of the Table, if it's set.
+	Query string
+	// Filter is a WHERE clause predicate, only the rows matching it are returned if it's set.
+	Filter          string
+	Columns         []string
+	OrderingColumns []string
+	// After holds values of the ordering columns of the last processed row.
+	// If it's set, only the rows that follow that row in the ordering are returned.
+	// It may hold values of only the leading ordering columns, then the rows that follow
+	// all the rows with these values are returned.
+	After map[string]any
+	// BoundColumn and BoundValue limit the rows to the ones which BoundColumn value
+	// is less than or equal to BoundValue, if BoundColumn is set.
+	BoundColumn string
+	BoundValue  any
+	// RangeColumn, RangeFrom and RangeTo limit the rows to the ones which RangeColumn value is greater than
+	// or equal to RangeFrom and less than RangeTo, if RangeColumn is set. A nil bound is not applied.
+	RangeColumn string
+	RangeFrom   any
+	RangeTo     any
+	Limit       int
+	Offset      int
+}
+
 // GetRows get rows from table.
-func (c *Client) GetRows(
-	ctx context.Context,
-	table string,
-	orderingColumns, columns []string,
-	limit, offset int,
-) ([]map[string]any, error) {
-	q := buildGetDataQuery(table, orderingColumns, columns, offset, limit)
+func (c *Client) GetRows(ctx context.Context, params GetRowsParams) ([]map[string]any, error) {
+	rows, _, err := c.GetRowsWithColumns(ctx, params)
+
+	return rows, err
+}
+
+// GetRowsWithColumns gets rows from table and the columns of the query result, in the order of the result.
+func (c *Client) GetRowsWithColumns(ctx context.Context, params GetRowsParams) ([]map[string]any, []Column, error) {
+	q, err := buildGetDataQuery(params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("build get data query: %w", err)
+	}
+
 	resp, err := c.RunQuery(ctx, q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("run query: %w", err)
+	}
+
+	if err = prepareRunQueryResponseData(resp); err != nil {
+		return nil, nil, fmt.Errorf("prepare run query response data: %w", err)
+	}
+
+	return resp.Data, columnsFromMeta(resp.Meta), nil
+}
+
+// GetMaxValueParams is an incoming params for the GetMaxValue and GetMinMaxValues methods.
+type GetMaxValueParams struct {
+	Table string
+	// Query is a SELECT query which result is read instead of the Table, if it's set.
+	Query string
+	// Filter is a WHERE clause predicate, only the rows matching it are aggregated if it's set.
+	Filter string
+	Column string
+}
+
+// GetMaxValue returns the maximum value of the column or nil if the table is empty.
+func (c *Client) GetMaxValue(ctx context.Context, params GetMaxValueParams) (any, error) {
+	values, err := c.getAggregates(ctx, params, aggregateMax)
+	if err != nil {
+		return nil, err
+	}
+
+	return values[aggregateAlias(aggregateMax)], nil
+}
+
+// GetMinMaxValues returns the minimum and maximum values of the column or nils if the table is empty.
+func (c *Client) GetMinMaxValues(ctx context.Context, params GetMaxValueParams) (any, any, error) {
+	values, err := c.getAggregates(ctx, params, aggregateMin, aggregateMax)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return values[aggregateAlias(aggregateMin)], values[aggregateAlias(aggregateMax)], nil
+}
+
+// getAggregates returns the values of the aggregate functions of the column by their aliases.
+func (c *Client) getAggregates(
+	ctx context.Context,
+	params GetMaxValueParams,
+	functions ...string,
+) (map[string]any, error) {
+	query, err := buildAggregateQuery(params, functions...)
+	if err != nil {
+		return nil, fmt.Errorf("build aggregate query: %w", err)
+	}
+
+	resp, err := c.RunQuery(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("run query: %w", err)
 	}
@@ -489,49 +742,288 @@ func (c *Client) GetRows(
 		return nil, fmt.Errorf("prepare run query response data: %w", err)
 	}
 
-	return resp.Data, nil
+	if len(resp.Data) == 0 {
+		return nil, nil
+	}
+
+	return resp.Data[0], nil
+}
+
+// InsertRowsParams is incoming params for the InsertRows method.
+type InsertRowsParams struct {
+	Table   string
+	Columns []string
+	Rows    [][]any
+	// MaxRows and MaxBytes limit the number of rows and the size of a single INSERT statement,
+	// zero means no limit. A statement always contains at least one row.
+	MaxRows  int
+	MaxBytes int
 }
 
-// InsertRow inserts a row into a table, with the provided columns and values.
-func (c *Client) InsertRow(ctx context.Context, table string, columns []string, values []any) error {
+// InsertRows inserts the rows into a table using multi-row INSERT statements.
+// It returns the number of inserted rows, which is less than the number of the rows if a statement fails.
+func (c *Client) InsertRows(ctx context.Context, params InsertRowsParams) (int, error) {
+	queries, err := buildInsertQueries(params)
+	if err != nil {
+		return 0, fmt.Errorf("build insert queries: %w", err)
+	}
+
+	var inserted int
+
+	for _, q := range queries {
+		if _, err = c.RunQuery(ctx, q.query); err != nil {
+			return inserted, fmt.Errorf("run query: %w", err)
+		}
+
+		inserted += q.rows
+	}
+
+	return inserted, nil
+}
+
+// UpdateRow updates the columns of the rows matching the key with the provided values.
+func (c *Client) UpdateRow(
+	ctx context.Context,
+	table string,
+	key map[string]any,
+	columns []string,
+	values []any,
+) error {
 	if len(columns) != len(values) {
 		return ErrColumnsValuesLenMismatch
 	}
 
-	query, err := buildInsertQuery(table, columns, values)
+	query, err := buildUpdateQuery(table, key, columns, values)
+	if err != nil {
+		return fmt.Errorf("build update query: %w", err)
+	}
+
+	if _, err = c.RunQuery(ctx, query); err != nil {
+		return fmt.Errorf("run query: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRow deletes the rows matching the key.
+func (c *Client) DeleteRow(ctx context.Context, table string, key map[string]any) error {
+	return c.DeleteRows(ctx, table, []map[string]any{key})
+}
+
+// DeleteRows deletes the rows matching any of the keys with a single statement.
+func (c *Client) DeleteRows(ctx context.Context, table string, keys []map[string]any) error {
+	query, err := buildDeleteQuery(table, keys)
 	if err != nil {
-		return fmt.Errorf("build insert query: %w", err)
+		return fmt.Errorf("build delete query: %w", err)
 	}
 
-	_, err = c.RunQuery(ctx, query)
+	if _, err = c.RunQuery(ctx, query); err != nil {
+		return fmt.Errorf("run query: %w", err)
+	}
+
+	return nil
+}
+
+// MergeRowsParams is incoming params for the MergeRows method.
+type MergeRowsParams struct {
+	Table   string
+	Columns []string
+	// KeyColumns - columns which values identify the rows, they must be a subset of the Columns.
+	KeyColumns []string
+	Rows       [][]any
+}
+
+// MergeRows updates the rows which key columns values match the ones of the provided rows
+// and inserts the rows that don't match, using a single MERGE statement.
+func (c *Client) MergeRows(ctx context.Context, params MergeRowsParams) error {
+	query, err := buildMergeQuery(params)
 	if err != nil {
+		return fmt.Errorf("build merge query: %w", err)
+	}
+
+	if _, err = c.RunQuery(ctx, query); err != nil {
 		return fmt.Errorf("run query: %w", err)
 	}
 
 	return nil
 }
 
-// GetColumnTypes get types columns.
+// GetColumnTypes returns the types of the table columns in lower case by the names of the columns.
 func (c *Client) GetColumnTypes(
 	ctx context.Context,
 	table string,
 ) (map[string]string, error) {
 	colTypes := make(map[string]string)
 
-	resp, err := c.RunQuery(ctx, fmt.Sprintf("describe %s", table))
+	quotedTable, err := QuoteIdentifier(table)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.RunQuery(ctx, "DESCRIBE "+quotedTable)
 	if err != nil {
 		return nil, fmt.Errorf("run query: %w", err)
 	}
 
+	// the names are kept as is, as the names of the columns created with quotes may be in any case.
 	for i := range resp.Data {
-		colTypes[strings.ToLower(fmt.Sprintf("%v",
-			resp.Data[i]["column_name"]))] = strings.ToLower(fmt.Sprintf("%v", resp.Data[i]["data_type"]))
+		column := fmt.Sprintf("%v", resp.Data[i]["column_name"])
+		colTypes[column] = strings.ToLower(fmt.Sprintf("%v", resp.Data[i]["data_type"]))
 	}
 
 	return colTypes, nil
 }
 
+// TableExists checks if the table exists in the database, under its name or under its name in lower case.
+func (c *Client) TableExists(ctx context.Context, table string) (bool, error) {
+	tables, err := c.ListTables(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(tables, resolveName(tables, table)), nil
+}
+
+// ResolveTable returns the name of the table in the database.
+// The names of the tables created without quotes are in lower case, so if there's no table with the name,
+// it's the name in lower case. The name is returned as is if neither of the tables exists.
+func (c *Client) ResolveTable(ctx context.Context, table string) (string, error) {
+	tables, err := c.ListTables(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return resolveName(tables, table), nil
+}
+
+// resolveName returns the name if it's in the names, otherwise the name in lower case if it's in the names,
+// or the name as is.
+func resolveName(names []string, name string) string {
+	if lower := strings.ToLower(name); !slices.Contains(names, name) && slices.Contains(names, lower) {
+		return lower
+	}
+
+	return name
+}
+
+// ResolveColumns returns the names of the columns in the table, each column is looked up
+// the same way as the table by the ResolveTable method.
+// It returns an error wrapping ErrColumnNotFound if the table has no column with the name
+// or with the name in lower case.
+func (c *Client) ResolveColumns(ctx context.Context, table string, columns []string) ([]string, error) {
+	if len(columns) == 0 {
+		return columns, nil
+	}
+
+	existing, err := c.ListColumns(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]string, len(columns))
+
+	for i, column := range columns {
+		resolved[i] = resolveName(existing, column)
+		if !slices.Contains(existing, resolved[i]) {
+			return nil, fmt.Errorf("%w: %q in the table %q", ErrColumnNotFound, column, table)
+		}
+	}
+
+	return resolved, nil
+}
+
+// ListColumns returns the names of the columns of the table.
+func (c *Client) ListColumns(ctx context.Context, table string) ([]string, error) {
+	quotedTable, err := QuoteIdentifier(table)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.RunQuery(ctx, "DESCRIBE "+quotedTable)
+	if err != nil {
+		return nil, fmt.Errorf("run query: %w", err)
+	}
+
+	columns := make([]string, 0, len(resp.Data))
+
+	for i := range resp.Data {
+		if column, ok := resp.Data[i]["column_name"].(string); ok {
+			columns = append(columns, column)
+		}
+	}
+
+	return columns, nil
+}
+
+// ListTables returns the names of the tables in the database.
+func (c *Client) ListTables(ctx context.Context) ([]string, error) {
+	resp, err := c.RunQuery(ctx, queryShowTables)
+	if err != nil {
+		return nil, fmt.Errorf("run query %q: %w", queryShowTables, err)
+	}
+
+	tables := make([]string, 0, len(resp.Data))
+
+	for i := range resp.Data {
+		if table, ok := resp.Data[i]["table_name"].(string); ok {
+			tables = append(tables, table)
+		}
+	}
+
+	return tables, nil
+}
+
+// ColumnDefinition is a definition of a column of a table to create.
+type ColumnDefinition struct {
+	Name     string
+	Type     string
+	Nullable bool
+}
+
+// CreateTableParams is incoming params for the CreateTable method.
+type CreateTableParams struct {
+	Table string
+	// Dimension - the table is a dimension table, otherwise it's a fact table.
+	Dimension    bool
+	Columns      []ColumnDefinition
+	PrimaryIndex []string
+}
+
+// CreateTable creates the table if it doesn't exist.
+func (c *Client) CreateTable(ctx context.Context, params CreateTableParams) error {
+	query, err := buildCreateTableQuery(params)
+	if err != nil {
+		return fmt.Errorf("build create table query: %w", err)
+	}
+
+	if _, err = c.RunQuery(ctx, query); err != nil {
+		return fmt.Errorf("run query: %w", err)
+	}
+
+	return nil
+}
+
+// AddColumn adds the column to the table.
+func (c *Client) AddColumn(ctx context.Context, table string, column ColumnDefinition) error {
+	quotedTable, err := QuoteIdentifier(table)
+	if err != nil {
+		return err
+	}
+
+	definition, err := buildColumnDefinition(column)
+	if err != nil {
+		return err
+	}
+
+	if _, err = c.RunQuery(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", quotedTable, definition)); err != nil {
+		return fmt.Errorf("run query: %w", err)
+	}
+
+	return nil
+}
+
 // GetPrimaryKeys returns the names of primary indexes columns.
+// The table is looked up the same way as by the ResolveTable method.
 func (c *Client) GetPrimaryKeys(
 	ctx context.Context,
 	table string,
@@ -543,48 +1035,351 @@ func (c *Client) GetPrimaryKeys(
 		return nil, fmt.Errorf("run query %q: %w", queryShowIndexes, err)
 	}
 
-	table = strings.ToLower(table)
+	expressions := make(map[string]string)
+	tables := make([]string, 0, len(resp.Data))
 
 	for i := range resp.Data {
-		if resp.Data[i]["table_name"] == table && resp.Data[i]["type"] == "primary" {
-			expression := resp.Data[i]["expression"].(string)
-
-			primaryKeys = strings.Split(expression[1:len(expression)-1], ",")
-
-			break
+		tableName, _ := resp.Data[i]["table_name"].(string)
+		if resp.Data[i]["type"] == "primary" {
+			expressions[tableName], _ = resp.Data[i]["expression"].(string)
+			tables = append(tables, tableName)
 		}
 	}
 
+	if expression := expressions[resolveName(tables, table)]; len(expression) > 1 {
+		primaryKeys = strings.Split(expression[1:len(expression)-1], ",")
+	}
+
 	return primaryKeys, nil
 }
 
-func buildGetDataQuery(table string, orderingColumns, fields []string, offset, limit int) string {
+// buildGetDataQuery generates an SQL SELECT statement query,
+// which reads the rows following the params.After values in the order of the ordering columns.
+func buildGetDataQuery(params GetRowsParams) (string, error) {
+	from, err := buildFrom(params.Table, params.Query)
+	if err != nil {
+		return "", err
+	}
+
+	orderingColumns, err := quoteIdentifiers(params.OrderingColumns)
+	if err != nil {
+		return "", err
+	}
+
 	sb := sqlbuilder.NewSelectBuilder()
 
-	if len(fields) == 0 {
+	if len(params.Columns) == 0 {
 		sb.Select("*")
 	} else {
-		sb.Select(fields...)
+		columns, er := quoteIdentifiers(params.Columns)
+		if er != nil {
+			return "", er
+		}
+
+		sb.Select(columns...)
+	}
+
+	sb.From(from)
+
+	if params.Filter != "" {
+		sb.Where(buildFilterCondition(params.Filter))
+	}
+
+	cond, err := buildKeysetCondition(&sb.Cond, params.OrderingColumns, params.After)
+	if err != nil {
+		return "", err
+	}
+
+	if cond != "" {
+		sb.Where(cond)
+	}
+
+	if params.BoundColumn != "" {
+		boundColumn, er := QuoteIdentifier(params.BoundColumn)
+		if er != nil {
+			return "", er
+		}
+
+		boundValue, er := literalArg(params.BoundValue)
+		if er != nil {
+			return "", er
+		}
+
+		sb.Where(sb.LessEqualThan(boundColumn, boundValue))
+	}
+
+	if params.RangeColumn != "" {
+		if err = buildRangeCondition(sb, params.RangeColumn, params.RangeFrom, params.RangeTo); err != nil {
+			return "", err
+		}
 	}
 
-	sb.From(table)
-	sb.Offset(offset)
-	sb.Limit(limit)
 	sb.OrderBy(orderingColumns...)
+	sb.Limit(params.Limit)
+
+	if params.Offset > 0 {
+		sb.Offset(params.Offset)
+	}
+
+	sql, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+	query, err := sqlbuilder.PostgreSQL.Interpolate(sql, args)
+	if err != nil {
+		return "", fmt.Errorf("interpolate arguments to SQL: %w", err)
+	}
 
-	return sb.String()
+	return query, nil
 }
 
-// buildInsertQuery generates an SQL INSERT statement query,
-// based on the provided table, columns and values.
-func buildInsertQuery(table string, columns []string, values []any) (string, error) {
-	sb := sqlbuilder.NewInsertBuilder()
+// buildGetMaxValueQuery generates an SQL SELECT statement query, which reads the maximum value of the column.
+func buildAggregateQuery(params GetMaxValueParams, functions ...string) (string, error) {
+	from, err := buildFrom(params.Table, params.Query)
+	if err != nil {
+		return "", err
+	}
 
-	sb.InsertInto(table)
-	sb.Cols(columns...)
-	sb.Values(values...)
+	column, err := QuoteIdentifier(params.Column)
+	if err != nil {
+		return "", err
+	}
 
-	sql, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
+	sb := sqlbuilder.NewSelectBuilder()
+
+	aggregates := make([]string, len(functions))
+	for i, function := range functions {
+		aggregates[i] = sb.As(fmt.Sprintf("%s(%s)", function, column), aggregateAlias(function))
+	}
+
+	sb.Select(aggregates...)
+	sb.From(from)
+
+	if params.Filter != "" {
+		sb.Where(buildFilterCondition(params.Filter))
+	}
+
+	return sb.String(), nil
+}
+
+// aggregateAlias returns an alias of the aggregated column of the function, e.g. max_value of MAX.
+func aggregateAlias(function string) string {
+	return strings.ToLower(function) + "_value"
+}
+
+// buildFrom returns the quoted table, or the query wrapped as an aliased subquery if it's set,
+// so that the rows of the query result are filtered and ordered the same way as the rows of a table.
+func buildFrom(table, query string) (string, error) {
+	if query == "" {
+		return QuoteIdentifier(table)
+	}
+
+	alias, err := QuoteIdentifier(queryAlias)
+	if err != nil {
+		return "", err
+	}
+
+	// a trailing semicolon is not allowed inside a subquery.
+	query = strings.TrimRight(strings.TrimSpace(query), "; \t\r\n")
+
+	// the dollar signs of the query are escaped, otherwise sqlbuilder treats them as placeholders.
+	return fmt.Sprintf("(%s) AS %s", sqlbuilder.Escape(query), alias), nil
+}
+
+// buildRangeCondition adds the conditions limiting the values of the column to the range from inclusive
+// to exclusive, a nil bound is not applied.
+func buildRangeCondition(sb *sqlbuilder.SelectBuilder, column string, from, to any) error {
+	column, err := QuoteIdentifier(column)
+	if err != nil {
+		return err
+	}
+
+	if from != nil {
+		value, er := literalArg(from)
+		if er != nil {
+			return er
+		}
+
+		sb.Where(sb.GreaterEqualThan(column, value))
+	}
+
+	if to != nil {
+		value, er := literalArg(to)
+		if er != nil {
+			return er
+		}
+
+		sb.Where(sb.LessThan(column, value))
+	}
+
+	return nil
+}
+
+// buildFilterCondition returns the filter predicate in parentheses, so that it's combined correctly
+// with the other conditions, e.g. the ones of the pagination, if it contains OR operators.
+func buildFilterCondition(filter string) string {
+	// the dollar signs of the filter are escaped, otherwise sqlbuilder treats them as placeholders.
+	return "(" + sqlbuilder.Escape(filter) + ")"
+}
+
+// buildKeysetCondition builds a condition matching the rows that follow the values in the order of the columns.
+// The row value comparison (a, b) > (x, y) is expanded to a > x OR (a = x AND b > y).
+// Only the leading columns that have values are compared, an empty string is returned if there are none.
+func buildKeysetCondition(cond *sqlbuilder.Cond, columns []string, values map[string]any) (string, error) {
+	for i, col := range columns {
+		if _, ok := values[col]; !ok {
+			columns = columns[:i]
+
+			break
+		}
+	}
+
+	if len(columns) == 0 {
+		return "", nil
+	}
+
+	quoted, err := quoteIdentifiers(columns)
+	if err != nil {
+		return "", err
+	}
+
+	literals := make([]any, len(columns))
+	for i, col := range columns {
+		if literals[i], err = literalArg(values[col]); err != nil {
+			return "", err
+		}
+	}
+
+	alternatives := make([]string, len(columns))
+
+	for i := range columns {
+		exprs := make([]string, 0, i+1)
+		for j := range columns[:i] {
+			exprs = append(exprs, cond.Equal(quoted[j], literals[j]))
+		}
+
+		exprs = append(exprs, cond.GreaterThan(quoted[i], literals[i]))
+
+		alternatives[i] = cond.And(exprs...)
+	}
+
+	return cond.Or(alternatives...), nil
+}
+
+// insertQuery is an INSERT statement and the number of rows it inserts.
+type insertQuery struct {
+	query string
+	rows  int
+}
+
+// buildInsertQueries generates SQL INSERT statements of the rows,
+// each statement contains as many rows as the limits of the params allow.
+func buildInsertQueries(params InsertRowsParams) ([]insertQuery, error) {
+	table, err := QuoteIdentifier(params.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := quoteIdentifiers(params.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", table, strings.Join(columns, ", "))
+
+	var (
+		queries []insertQuery
+		sb      strings.Builder
+		rows    int
+	)
+
+	for _, row := range params.Rows {
+		if len(row) != len(params.Columns) {
+			return nil, ErrColumnsValuesLenMismatch
+		}
+
+		values, err := buildInsertValues(row)
+		if err != nil {
+			return nil, err
+		}
+
+		if rows > 0 && ((params.MaxRows > 0 && rows >= params.MaxRows) ||
+			(params.MaxBytes > 0 && sb.Len()+len(", ")+len(values) > params.MaxBytes)) {
+			queries = append(queries, insertQuery{query: sb.String(), rows: rows})
+
+			sb.Reset()
+			rows = 0
+		}
+
+		if rows == 0 {
+			sb.WriteString(prefix)
+		} else {
+			sb.WriteString(", ")
+		}
+
+		sb.WriteString(values)
+		rows++
+	}
+
+	if rows > 0 {
+		queries = append(queries, insertQuery{query: sb.String(), rows: rows})
+	}
+
+	return queries, nil
+}
+
+// buildInsertValues generates a row of values of an SQL INSERT statement, e.g. (1, E'name').
+func buildInsertValues(row []any) (string, error) {
+	literals := make([]string, len(row))
+
+	for i := range row {
+		var err error
+		if literals[i], err = FormatLiteral(row[i]); err != nil {
+			return "", err
+		}
+	}
+
+	return "(" + strings.Join(literals, ", ") + ")", nil
+}
+
+// buildUpdateQuery generates an SQL UPDATE statement query,
+// based on the provided table, key, columns and values.
+func buildUpdateQuery(table string, key map[string]any, columns []string, values []any) (string, error) {
+	if len(key) == 0 {
+		return "", ErrEmptyKey
+	}
+
+	table, err := QuoteIdentifier(table)
+	if err != nil {
+		return "", err
+	}
+
+	ub := sqlbuilder.NewUpdateBuilder()
+
+	ub.Update(table)
+
+	assignments := make([]string, len(columns))
+	for i := range columns {
+		column, er := QuoteIdentifier(columns[i])
+		if er != nil {
+			return "", er
+		}
+
+		value, er := literalArg(values[i])
+		if er != nil {
+			return "", er
+		}
+
+		assignments[i] = ub.Assign(column, value)
+	}
+
+	keyCondition, err := buildKeyCondition(&ub.Cond, key)
+	if err != nil {
+		return "", err
+	}
+
+	ub.Set(assignments...)
+	ub.Where(keyCondition...)
+
+	sql, args := ub.BuildWithFlavor(sqlbuilder.PostgreSQL)
 
 	query, err := sqlbuilder.PostgreSQL.Interpolate(sql, args)
 	if err != nil {
@@ -594,59 +1389,250 @@ func buildInsertQuery(table string, columns []string, values []any) (string, err
 	return query, nil
 }
 
-// prepareRunQueryResponseData converts resp.Data values to the appropriate Go types.
-// For example if we query a Firebolt's table containing boolean values,
-// Firebolt will return them as UInt8, but other connectors such as Postgres and Materialize
-// don't accept UInt8 as boolean.
-func prepareRunQueryResponseData(resp *RunQueryResponse) error {
-	mutations := make(map[string]func(any) (any, error))
+// buildDeleteQuery generates an SQL DELETE statement query, based on the provided table and keys.
+func buildDeleteQuery(table string, keys []map[string]any) (string, error) {
+	if len(keys) == 0 {
+		return "", ErrEmptyKey
+	}
 
-	for _, meta := range resp.Meta {
-		// UInt8 is a Firebolt's representation of a boolean type.
-		if meta.Type == MetaTypeUInt8 {
-			mutations[meta.Name] = func(value any) (any, error) {
-				// use float64 here cause Go unmarshals JSON's integer type into
-				// an empty interface as float64.
-				parsed, ok := value.(float64)
-				if !ok {
-					return nil, ErrCannotCastValueToFloat64
-				}
+	table, err := QuoteIdentifier(table)
+	if err != nil {
+		return "", err
+	}
 
-				return parsed != 0, nil
-			}
+	db := sqlbuilder.NewDeleteBuilder()
+
+	db.DeleteFrom(table)
+
+	keyConditions := make([][]string, len(keys))
+	for i, key := range keys {
+		if len(key) == 0 {
+			return "", ErrEmptyKey
 		}
-		if meta.Type == MetaTypeDATE || meta.Type == MetaTypeTIMESTAMP ||
-			meta.Type == MetaTypeNullableTIMESTAMP || meta.Type == MetaTypeNullableDATE {
-			mutations[meta.Name] = func(value any) (any, error) {
-				if value == nil {
-					return nil, nil
-				}
 
-				parsed, ok := value.(string)
-				if !ok {
-					return nil, ErrCannotCastValueToString
-				}
+		if keyConditions[i], err = buildKeyCondition(&db.Cond, key); err != nil {
+			return "", err
+		}
+	}
 
-				t, err := parseTime(parsed)
-				if err != nil {
-					return nil, ErrCannotParseTime
-				}
+	if len(keys) == 1 {
+		db.Where(keyConditions[0]...)
+	} else {
+		conditions := make([]string, len(keys))
+		for i := range keyConditions {
+			conditions[i] = db.And(keyConditions[i]...)
+		}
+
+		db.Where(db.Or(conditions...))
+	}
+
+	sql, args := db.BuildWithFlavor(sqlbuilder.PostgreSQL)
 
-				return t, nil
+	query, err := sqlbuilder.PostgreSQL.Interpolate(sql, args)
+	if err != nil {
+		return "", fmt.Errorf("interpolate arguments to SQL: %w", err)
+	}
+
+	return query, nil
+}
+
+// buildCreateTableQuery generates an SQL CREATE TABLE statement query, based on the provided params.
+func buildCreateTableQuery(params CreateTableParams) (string, error) {
+	tableType := "FACT"
+	if params.Dimension {
+		tableType = "DIMENSION"
+	}
+
+	table, err := QuoteIdentifier(params.Table)
+	if err != nil {
+		return "", err
+	}
+
+	columns := make([]string, len(params.Columns))
+	for i := range params.Columns {
+		if columns[i], err = buildColumnDefinition(params.Columns[i]); err != nil {
+			return "", err
+		}
+	}
+
+	query := fmt.Sprintf("CREATE %s TABLE IF NOT EXISTS %s (%s)", tableType, table, strings.Join(columns, ", "))
+
+	if len(params.PrimaryIndex) > 0 {
+		primaryIndex, er := quoteIdentifiers(params.PrimaryIndex)
+		if er != nil {
+			return "", er
+		}
+
+		query += " PRIMARY INDEX " + strings.Join(primaryIndex, ", ")
+	}
+
+	return query, nil
+}
+
+// buildColumnDefinition returns the column definition of CREATE TABLE and ALTER TABLE statements.
+func buildColumnDefinition(column ColumnDefinition) (string, error) {
+	name, err := QuoteIdentifier(column.Name)
+	if err != nil {
+		return "", err
+	}
+
+	if column.Nullable {
+		return fmt.Sprintf("%s %s NULL", name, column.Type), nil
+	}
+
+	return fmt.Sprintf("%s %s NOT NULL", name, column.Type), nil
+}
+
+// buildMergeQuery generates an SQL MERGE statement query, which source is the provided rows.
+func buildMergeQuery(params MergeRowsParams) (string, error) {
+	if len(params.KeyColumns) == 0 {
+		return "", ErrEmptyKey
+	}
+
+	table, err := QuoteIdentifier(params.Table)
+	if err != nil {
+		return "", err
+	}
+
+	columns, err := quoteIdentifiers(params.Columns)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		on      []string
+		set     []string
+		targets = make([]string, len(columns))
+	)
+
+	for i, col := range columns {
+		targets[i] = "source." + col
+
+		if slices.Contains(params.KeyColumns, params.Columns[i]) {
+			on = append(on, fmt.Sprintf("target.%s = source.%s", col, col))
+
+			continue
+		}
+
+		set = append(set, fmt.Sprintf("%s = source.%s", col, col))
+	}
+
+	if len(on) != len(params.KeyColumns) {
+		return "", fmt.Errorf("key columns %v must be a subset of the columns %v", params.KeyColumns, params.Columns)
+	}
+
+	selects := make([]string, len(params.Rows))
+
+	for i, row := range params.Rows {
+		if len(row) != len(columns) {
+			return "", ErrColumnsValuesLenMismatch
+		}
+
+		items := make([]string, len(row))
+		for j := range row {
+			literal, er := FormatLiteral(row[j])
+			if er != nil {
+				return "", er
 			}
+
+			items[j] = literal + " AS " + columns[j]
+		}
+
+		selects[i] = "SELECT " + strings.Join(items, ", ")
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "MERGE INTO %s AS target USING (%s) AS source ON %s",
+		table, strings.Join(selects, " UNION ALL "), strings.Join(on, " AND "))
+
+	if len(set) > 0 {
+		fmt.Fprintf(&sb, " WHEN MATCHED THEN UPDATE SET %s", strings.Join(set, ", "))
+	}
+
+	fmt.Fprintf(&sb, " WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)",
+		strings.Join(columns, ", "), strings.Join(targets, ", "))
+
+	return sb.String(), nil
+}
+
+// buildKeyCondition returns the conditions matching the rows by the key, ordered by the key column names.
+func buildKeyCondition(cond *sqlbuilder.Cond, key map[string]any) ([]string, error) {
+	columns := make([]string, 0, len(key))
+	for col := range key {
+		columns = append(columns, col)
+	}
+
+	slices.Sort(columns)
+
+	exprs := make([]string, len(columns))
+	for i, col := range columns {
+		quoted, err := QuoteIdentifier(col)
+		if err != nil {
+			return nil, err
+		}
+
+		if key[col] == nil {
+			exprs[i] = cond.IsNull(quoted)
+
+			continue
+		}
+
+		value, err := literalArg(key[col])
+		if err != nil {
+			return nil, err
 		}
+
+		exprs[i] = cond.Equal(quoted, value)
+	}
+
+	return exprs, nil
+}
+
+// ConvertValues converts the values of the columns to the Go types of the column types in place, the same way as
+// the values of the rows are converted, e.g. the timestamps encoded as strings to time.Time.
+// The values of the columns missing in the columns and the values which are already converted are left as is.
+func ConvertValues(values map[string]any, columns []Column) error {
+	for _, col := range columns {
+		value, ok := values[col.Name]
+		if !ok {
+			continue
+		}
+
+		columnType := parseColumnType(col.Type)
+		if reflect.TypeOf(value) == columnType.goType() {
+			continue
+		}
+
+		var err error
+		if values[col.Name], err = columnType.convert(value); err != nil {
+			return fmt.Errorf("convert %q value of %q type: %w", col.Name, columnType.name, err)
+		}
+	}
+
+	return nil
+}
+
+// prepareRunQueryResponseData converts resp.Data values to the Go types of the column types of resp.Meta,
+// e.g. integers to int64, decimals to json.Number, dates and timestamps to time.Time,
+// and arrays to slices of the converted elements. The values of unknown types are left as is.
+func prepareRunQueryResponseData(resp *RunQueryResponse) error {
+	columnTypes := make(map[string]columnType, len(resp.Meta))
+	for _, meta := range resp.Meta {
+		columnTypes[meta.Name] = parseColumnType(meta.Type)
 	}
 
 	var err error
 	for _, row := range resp.Data {
 		for key, value := range row {
-			if _, ok := mutations[key]; !ok {
+			columnType, ok := columnTypes[key]
+			if !ok {
 				continue
 			}
 
-			row[key], err = mutations[key](value)
+			row[key], err = columnType.convert(value)
 			if err != nil {
-				return fmt.Errorf("mutate %q key: %w", key, err)
+				return fmt.Errorf("convert %q value of %q type: %w", key, columnType.name, err)
 			}
 		}
 	}
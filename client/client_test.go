@@ -0,0 +1,648 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client/fireboltest"
+)
+
+func TestBuildInsertQueries(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  InsertRowsParams
+		want    []insertQuery
+		wantErr bool
+	}{
+		{
+			name: "single statement",
+			params: InsertRowsParams{
+				Table:   "users",
+				Columns: []string{"id", "name"},
+				Rows:    [][]any{{1, "one"}, {2, "it's two"}},
+			},
+			want: []insertQuery{
+				{query: `INSERT INTO "users" ("id", "name") VALUES (1, E'one'), (2, E'it\'s two')`, rows: 2},
+			},
+		},
+		{
+			name: "max rows",
+			params: InsertRowsParams{
+				Table:   "users",
+				Columns: []string{"id"},
+				Rows:    [][]any{{1}, {2}, {3}},
+				MaxRows: 2,
+			},
+			want: []insertQuery{
+				{query: `INSERT INTO "users" ("id") VALUES (1), (2)`, rows: 2},
+				{query: `INSERT INTO "users" ("id") VALUES (3)`, rows: 1},
+			},
+		},
+		{
+			name: "max bytes",
+			params: InsertRowsParams{
+				Table:    "users",
+				Columns:  []string{"id"},
+				Rows:     [][]any{{1}, {2}, {3}},
+				MaxBytes: len(`INSERT INTO "users" ("id") VALUES (1), (2)`),
+			},
+			want: []insertQuery{
+				{query: `INSERT INTO "users" ("id") VALUES (1), (2)`, rows: 2},
+				{query: `INSERT INTO "users" ("id") VALUES (3)`, rows: 1},
+			},
+		},
+		{
+			name: "row larger than max bytes",
+			params: InsertRowsParams{
+				Table:    "users",
+				Columns:  []string{"id"},
+				Rows:     [][]any{{1}, {2}},
+				MaxBytes: 1,
+			},
+			want: []insertQuery{
+				{query: `INSERT INTO "users" ("id") VALUES (1)`, rows: 1},
+				{query: `INSERT INTO "users" ("id") VALUES (2)`, rows: 1},
+			},
+		},
+		{
+			name: "columns and values mismatch",
+			params: InsertRowsParams{
+				Table:   "users",
+				Columns: []string{"id", "name"},
+				Rows:    [][]any{{1}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildInsertQueries(tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("build error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("build = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildGetDataQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  GetRowsParams
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "table",
+			params: GetRowsParams{Table: "users", OrderingColumns: []string{"id"}, Limit: 10},
+			want:   `SELECT * FROM "users" ORDER BY "id" LIMIT 10`,
+		},
+		{
+			name: "query after the last processed row",
+			params: GetRowsParams{
+				Query:           "SELECT id, name FROM users WHERE id > 10;\n",
+				Columns:         []string{"id", "name"},
+				OrderingColumns: []string{"id"},
+				After:           map[string]any{"id": 20},
+				Limit:           10,
+			},
+			want: `SELECT "id", "name" FROM (SELECT id, name FROM users WHERE id > 10) AS "query" ` +
+				`WHERE (("id" > 20)) ORDER BY "id" LIMIT 10`,
+		},
+		{
+			name: "filter combined with the pagination",
+			params: GetRowsParams{
+				Table:           "users",
+				Filter:          "tenant_id = 1 OR tenant_id = 2",
+				OrderingColumns: []string{"id"},
+				After:           map[string]any{"id": 20},
+				Limit:           10,
+			},
+			want: `SELECT * FROM "users" WHERE (tenant_id = 1 OR tenant_id = 2) AND (("id" > 20)) ` +
+				`ORDER BY "id" LIMIT 10`,
+		},
+		{
+			name: "partition range",
+			params: GetRowsParams{
+				Table:           "users",
+				OrderingColumns: []string{"id"},
+				RangeColumn:     "id",
+				RangeFrom:       int64(100),
+				RangeTo:         int64(200),
+				Limit:           10,
+			},
+			want: `SELECT * FROM "users" WHERE "id" >= 100 AND "id" < 200 ORDER BY "id" LIMIT 10`,
+		},
+		{
+			name: "query with dollar signs",
+			params: GetRowsParams{
+				Query:           "SELECT id FROM users WHERE name = '$1'",
+				OrderingColumns: []string{"id"},
+				Limit:           10,
+			},
+			want: `SELECT * FROM (SELECT id FROM users WHERE name = '$1') AS "query" ORDER BY "id" LIMIT 10`,
+		},
+		{
+			name:    "invalid identifier",
+			params:  GetRowsParams{Table: "users", OrderingColumns: []string{""}, Limit: 10},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildGetDataQuery(tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("build error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("build = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildUpdateQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     map[string]any
+		columns []string
+		values  []any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "values of different types",
+			key:     map[string]any{"id": 1},
+			columns: []string{"name", "score", "deleted_at"},
+			values:  []any{"it's", 1.5, nil},
+			want:    `UPDATE "users" SET "name" = E'it\'s', "score" = 1.5, "deleted_at" = NULL WHERE "id" = 1`,
+		},
+		{
+			name:    "empty key",
+			columns: []string{"name"},
+			values:  []any{"one"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid identifier",
+			key:     map[string]any{"id": 1},
+			columns: []string{""},
+			values:  []any{"one"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildUpdateQuery("users", tt.key, tt.columns, tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("build error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("build = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildDeleteQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		table   string
+		keys    []map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "single key",
+			keys: []map[string]any{{"name": "one", "id": 1}},
+			want: `DELETE FROM "users" WHERE "id" = 1 AND "name" = E'one'`,
+		},
+		{
+			name: "multiple keys",
+			keys: []map[string]any{{"id": 1}, {"id": nil}},
+			want: `DELETE FROM "users" WHERE (("id" = 1) OR ("id" IS NULL))`,
+		},
+		{
+			name:    "empty key",
+			keys:    []map[string]any{{"id": 1}, {}},
+			wantErr: true,
+		},
+		{
+			name:  "identifiers with quotes and literals with escapes",
+			table: `my "users"`,
+			keys:  []map[string]any{{`name"; DROP TABLE users; --`: "it's\\\n"}},
+			want:  `DELETE FROM "my ""users""" WHERE "name""; DROP TABLE users; --" = E'it\'s\\\n'`,
+		},
+		{
+			name:    "invalid identifier",
+			keys:    []map[string]any{{"": 1}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := tt.table
+			if table == "" {
+				table = "users"
+			}
+
+			got, err := buildDeleteQuery(table, tt.keys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("build error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("build = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildMergeQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  MergeRowsParams
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "update and insert",
+			params: MergeRowsParams{
+				Table:      "users",
+				Columns:    []string{"id", "name"},
+				KeyColumns: []string{"id"},
+				Rows:       [][]any{{1, "one"}, {2, "two"}},
+			},
+			want: `MERGE INTO "users" AS target USING (SELECT 1 AS "id", E'one' AS "name" UNION ALL ` +
+				`SELECT 2 AS "id", E'two' AS "name") AS source ON target."id" = source."id" ` +
+				`WHEN MATCHED THEN UPDATE SET "name" = source."name" ` +
+				`WHEN NOT MATCHED THEN INSERT ("id", "name") VALUES (source."id", source."name")`,
+		},
+		{
+			name: "only key columns",
+			params: MergeRowsParams{
+				Table:      "users",
+				Columns:    []string{"id"},
+				KeyColumns: []string{"id"},
+				Rows:       [][]any{{1}},
+			},
+			want: `MERGE INTO "users" AS target USING (SELECT 1 AS "id") AS source ON target."id" = source."id" ` +
+				`WHEN NOT MATCHED THEN INSERT ("id") VALUES (source."id")`,
+		},
+		{
+			name: "key column is not in the columns",
+			params: MergeRowsParams{
+				Table:      "users",
+				Columns:    []string{"name"},
+				KeyColumns: []string{"id"},
+				Rows:       [][]any{{"one"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildMergeQuery(tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("build error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("build = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildCreateTableQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		params CreateTableParams
+		want   string
+	}{
+		{
+			name: "fact table with primary index",
+			params: CreateTableParams{
+				Table: "users",
+				Columns: []ColumnDefinition{
+					{Name: "id", Type: "BIGINT"},
+					{Name: "name", Type: "TEXT", Nullable: true},
+				},
+				PrimaryIndex: []string{"id"},
+			},
+			want: `CREATE FACT TABLE IF NOT EXISTS "users" ("id" BIGINT NOT NULL, "name" TEXT NULL) ` +
+				`PRIMARY INDEX "id"`,
+		},
+		{
+			name: "dimension table without primary index",
+			params: CreateTableParams{
+				Table:     "users",
+				Dimension: true,
+				Columns:   []ColumnDefinition{{Name: "name", Type: "TEXT", Nullable: true}},
+			},
+			want: `CREATE DIMENSION TABLE IF NOT EXISTS "users" ("name" TEXT NULL)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildCreateTableQuery(tt.params)
+			if err != nil {
+				t.Fatalf("build error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("build = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_InsertRows_PartialFailure(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	if err := srv.Exec(fireboltest.DB, "CREATE DIMENSION TABLE users (id INT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	cl := New(ctx, srv.URL, fireboltest.DB)
+	defer cl.Close(ctx)
+
+	err := cl.Login(ctx, LoginParams{
+		Email:       fireboltest.Email,
+		Password:    fireboltest.Password,
+		AccountName: fireboltest.AccountName,
+		EngineName:  fireboltest.EngineName,
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	// the second statement fails, so only the rows of the first one are inserted.
+	inserted, err := cl.InsertRows(ctx, InsertRowsParams{
+		Table:   "users",
+		Columns: []string{"id"},
+		Rows:    [][]any{{1}, {2}, {3}, {"four"}},
+		MaxRows: 2,
+	})
+	if err == nil {
+		t.Fatal("want error")
+	}
+
+	if inserted != 2 {
+		t.Errorf("inserted = %d, want 2", inserted)
+	}
+
+	rows, err := cl.GetRows(ctx, GetRowsParams{Table: "users", OrderingColumns: []string{"id"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	if len(rows) != inserted {
+		t.Errorf("got %d rows, want %d", len(rows), inserted)
+	}
+}
+
+func TestClient_QuotedIdentifiers(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	cl := New(ctx, srv.URL, fireboltest.DB)
+	defer cl.Close(ctx)
+
+	err := cl.Login(ctx, LoginParams{
+		Email:       fireboltest.Email,
+		Password:    fireboltest.Password,
+		AccountName: fireboltest.AccountName,
+		EngineName:  fireboltest.EngineName,
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	// the names are case-sensitive, contain spaces and reserved words.
+	err = cl.CreateTable(ctx, CreateTableParams{
+		Table:     "Order Items",
+		Dimension: true,
+		Columns:   []ColumnDefinition{{Name: "ID", Type: "INT"}, {Name: "select", Type: "TEXT", Nullable: true}},
+	})
+	if err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	_, err = cl.InsertRows(ctx, InsertRowsParams{
+		Table:   "Order Items",
+		Columns: []string{"ID", "select"},
+		Rows:    [][]any{{1, "it's"}, {2, "two"}},
+	})
+	if err != nil {
+		t.Fatalf("insert rows: %v", err)
+	}
+
+	rows, err := cl.GetRows(ctx, GetRowsParams{
+		Table:           "Order Items",
+		OrderingColumns: []string{"ID"},
+		After:           map[string]any{"ID": 1},
+		Limit:           10,
+	})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	want := []map[string]any{{"ID": int64(2), "select": "two"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+
+	// an invalid identifier is rejected before the query is sent.
+	var identErr *InvalidIdentifierError
+	if _, err = cl.GetColumnTypes(ctx, ""); !errors.As(err, &identErr) {
+		t.Errorf("get column types error = %v, want %T", err, identErr)
+	}
+}
+
+func TestClient_MixedCaseTableNames(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	for _, query := range []string{
+		"CREATE FACT TABLE users (id INT, name TEXT) PRIMARY INDEX id",
+		`CREATE DIMENSION TABLE "Events" (id INT)`,
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl := New(ctx, srv.URL, fireboltest.DB)
+	defer cl.Close(ctx)
+
+	err := cl.Login(ctx, LoginParams{
+		Email:       fireboltest.Email,
+		Password:    fireboltest.Password,
+		AccountName: fireboltest.AccountName,
+		EngineName:  fireboltest.EngineName,
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		table           string
+		wantTable       string
+		wantExists      bool
+		wantPrimaryKeys []string
+	}{
+		{
+			name:            "unquoted table in mixed case",
+			table:           "Users",
+			wantTable:       "users",
+			wantExists:      true,
+			wantPrimaryKeys: []string{"id"},
+		},
+		{
+			name:       "quoted table in mixed case",
+			table:      "Events",
+			wantTable:  "Events",
+			wantExists: true,
+		},
+		{
+			name:      "missing table",
+			table:     "Orders",
+			wantTable: "Orders",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := cl.ResolveTable(ctx, tt.table)
+			if err != nil {
+				t.Fatalf("resolve table: %v", err)
+			}
+
+			if table != tt.wantTable {
+				t.Errorf("resolve table = %q, want %q", table, tt.wantTable)
+			}
+
+			exists, err := cl.TableExists(ctx, tt.table)
+			if err != nil {
+				t.Fatalf("table exists: %v", err)
+			}
+
+			if exists != tt.wantExists {
+				t.Errorf("table exists = %t, want %t", exists, tt.wantExists)
+			}
+
+			primaryKeys, err := cl.GetPrimaryKeys(ctx, tt.table)
+			if err != nil {
+				t.Fatalf("get primary keys: %v", err)
+			}
+
+			if !reflect.DeepEqual(primaryKeys, tt.wantPrimaryKeys) {
+				t.Errorf("get primary keys = %v, want %v", primaryKeys, tt.wantPrimaryKeys)
+			}
+		})
+	}
+}
+
+func TestClient_ResolveColumns(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	if err := srv.Exec(fireboltest.DB, `CREATE DIMENSION TABLE users (id INT, "UserName" TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+
+	cl := New(ctx, srv.URL, fireboltest.DB)
+	defer cl.Close(ctx)
+
+	err := cl.Login(ctx, LoginParams{
+		Email:       fireboltest.Email,
+		Password:    fireboltest.Password,
+		AccountName: fireboltest.AccountName,
+		EngineName:  fireboltest.EngineName,
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		columns []string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "exact names",
+			columns: []string{"id", "UserName"},
+			want:    []string{"id", "UserName"},
+		},
+		{
+			name:    "unquoted column in upper case",
+			columns: []string{"ID"},
+			want:    []string{"id"},
+		},
+		{
+			name:    "quoted column in lower case",
+			columns: []string{"username"},
+			wantErr: ErrColumnNotFound,
+		},
+		{
+			name:    "missing column",
+			columns: []string{"id", "email"},
+			wantErr: ErrColumnNotFound,
+		},
+		{
+			name: "no columns",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cl.ResolveColumns(ctx, "users", tt.columns)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("resolve columns error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolve columns = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
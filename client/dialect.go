@@ -0,0 +1,219 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/huandu/go-sqlbuilder"
+)
+
+// timestampLiteralLayout is a layout of timestamp literals.
+const timestampLiteralLayout = "2006-01-02 15:04:05.999999"
+
+// jsonNumber matches the numbers of the JSON grammar, it doesn't match NaN and infinite numbers.
+var jsonNumber = regexp.MustCompile(`^-?(?:0|[1-9][0-9]*)(?:\.[0-9]+)?(?:[eE][+-]?[0-9]+)?$`)
+
+// InvalidIdentifierError occurs when a table or a column name can't be used in a query.
+type InvalidIdentifierError struct {
+	Identifier string
+	Reason     string
+}
+
+// Error returns the error message.
+func (e *InvalidIdentifierError) Error() string {
+	return fmt.Sprintf("invalid identifier %q: %s", e.Identifier, e.Reason)
+}
+
+// UnsupportedValueError occurs when a value can't be written as an SQL literal.
+type UnsupportedValueError struct {
+	Value  any
+	Reason string
+}
+
+// Error returns the error message.
+func (e *UnsupportedValueError) Error() string {
+	return fmt.Sprintf("unsupported value %v (%T): %s", e.Value, e.Value, e.Reason)
+}
+
+// QuoteIdentifier returns the identifier enclosed in double quotes, the double quotes inside it are doubled.
+// Quoted identifiers are case-sensitive and may contain spaces and reserved words.
+// It returns an *InvalidIdentifierError if the identifier is empty, isn't valid UTF-8
+// or contains control characters.
+func QuoteIdentifier(name string) (string, error) {
+	switch {
+	case name == "":
+		return "", &InvalidIdentifierError{Identifier: name, Reason: "identifier is empty"}
+	case !utf8.ValidString(name):
+		return "", &InvalidIdentifierError{Identifier: name, Reason: "identifier is not valid UTF-8"}
+	case strings.IndexFunc(name, unicode.IsControl) >= 0:
+		return "", &InvalidIdentifierError{Identifier: name, Reason: "identifier contains control characters"}
+	}
+
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`, nil
+}
+
+// quoteIdentifiers returns the quoted identifiers.
+func quoteIdentifiers(names []string) ([]string, error) {
+	quoted := make([]string, len(names))
+
+	for i := range names {
+		var err error
+		if quoted[i], err = QuoteIdentifier(names[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return quoted, nil
+}
+
+// FormatLiteral returns the SQL literal of the value.
+// Strings are escape strings (E'...'), times are timestamp literals, slices and arrays are array literals,
+// and numbers and booleans are written as is. It returns an *UnsupportedValueError for values of other types,
+// NaN and infinite numbers and invalid UTF-8 strings.
+func FormatLiteral(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return formatString(v)
+	case []byte:
+		return formatString(string(v))
+	case json.Number:
+		if !jsonNumber.MatchString(string(v)) {
+			return "", &UnsupportedValueError{Value: value, Reason: "invalid number"}
+		}
+
+		return string(v), nil
+	case time.Time:
+		// the literal has no offset, so the time is written in UTC.
+		return "'" + v.UTC().Format(timestampLiteralLayout) + "'", nil
+	case *big.Int:
+		if v == nil {
+			return "NULL", nil
+		}
+
+		return v.String(), nil
+	}
+
+	return formatKind(value)
+}
+
+// formatKind returns the SQL literal of the value by its kind.
+func formatKind(value any) (string, error) {
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			return "TRUE", nil
+		}
+
+		return "FALSE", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", &UnsupportedValueError{Value: value, Reason: "NaN and infinite numbers are not supported"}
+		}
+
+		return strconv.FormatFloat(f, 'g', -1, rv.Type().Bits()), nil
+	case reflect.String:
+		return formatString(rv.String())
+	case reflect.Slice, reflect.Array:
+		elems := make([]string, rv.Len())
+
+		for i := range elems {
+			var err error
+			if elems[i], err = FormatLiteral(rv.Index(i).Interface()); err != nil {
+				return "", err
+			}
+		}
+
+		return "[" + strings.Join(elems, ", ") + "]", nil
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return "NULL", nil
+		}
+
+		return FormatLiteral(rv.Elem().Interface())
+	default:
+		return "", &UnsupportedValueError{Value: value, Reason: "type is not supported"}
+	}
+}
+
+// formatString returns the escape string literal of the string,
+// the quotes, the backslashes and the control characters are escaped.
+func formatString(s string) (string, error) {
+	if !utf8.ValidString(s) {
+		return "", &UnsupportedValueError{Value: s, Reason: "string is not valid UTF-8"}
+	}
+
+	var sb strings.Builder
+
+	sb.Grow(len(s) + len("E''"))
+	sb.WriteString("E'")
+
+	for _, r := range s {
+		switch r {
+		case '\'':
+			sb.WriteString(`\'`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case 0:
+			sb.WriteString(`\0`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	sb.WriteString("'")
+
+	return sb.String(), nil
+}
+
+// literalArg returns the literal of the value as a raw argument of sqlbuilder,
+// which is inserted into the query as is.
+func literalArg(value any) (any, error) {
+	literal, err := FormatLiteral(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlbuilder.Raw(literal), nil
+}
@@ -0,0 +1,135 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		ident   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "lower case",
+			ident: "users",
+			want:  `"users"`,
+		},
+		{
+			name:  "mixed case, spaces and reserved word",
+			ident: "Order Items",
+			want:  `"Order Items"`,
+		},
+		{
+			name:  "double quotes",
+			ident: `a"b`,
+			want:  `"a""b"`,
+		},
+		{
+			name:    "empty",
+			ident:   "",
+			wantErr: true,
+		},
+		{
+			name:    "control character",
+			ident:   "users\x00",
+			wantErr: true,
+		},
+		{
+			name:    "invalid UTF-8",
+			ident:   "users\xff",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := QuoteIdentifier(tt.ident)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("quote error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			var identErr *InvalidIdentifierError
+			if tt.wantErr && !errors.As(err, &identErr) {
+				t.Errorf("quote error = %T, want %T", err, identErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("quote = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatLiteral(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    string
+		wantErr bool
+	}{
+		{name: "null", value: nil, want: "NULL"},
+		{name: "boolean", value: true, want: "TRUE"},
+		{name: "int", value: int64(-42), want: "-42"},
+		{name: "uint", value: uint64(math.MaxUint64), want: "18446744073709551615"},
+		{name: "big int", value: new(big.Int).Lsh(big.NewInt(1), 100), want: "1267650600228229401496703205376"},
+		{name: "float", value: 1.5, want: "1.5"},
+		{name: "json number", value: json.Number("9007199254740993"), want: "9007199254740993"},
+		{name: "string", value: "it's", want: `E'it\'s'`},
+		{name: "string with escapes", value: "a\\b\n\t\x00", want: `E'a\\b\n\t\0'`},
+		{name: "time", value: time.Date(2022, 6, 1, 10, 0, 0, 5000, time.UTC), want: "'2022-06-01 10:00:00.000005'"},
+		{
+			name:  "time with offset",
+			value: time.Date(2022, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			want:  "'2022-06-01 10:00:00'",
+		},
+		{name: "array", value: []any{1, "a", nil}, want: "[1, E'a', NULL]"},
+		{name: "pointer", value: new(int), want: "0"},
+		{name: "NaN", value: math.NaN(), wantErr: true},
+		{name: "infinity", value: math.Inf(1), wantErr: true},
+		{name: "invalid json number", value: json.Number("1; DROP TABLE users"), wantErr: true},
+		{name: "NaN json number", value: json.Number("NaN"), wantErr: true},
+		{name: "infinite json number", value: json.Number("+Inf"), wantErr: true},
+		{name: "json number with exponent", value: json.Number("-1.5e+10"), want: "-1.5e+10"},
+		{name: "invalid UTF-8 string", value: "\xff", wantErr: true},
+		{name: "map", value: map[string]any{"a": 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatLiteral(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("format error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			var valueErr *UnsupportedValueError
+			if tt.wantErr && !errors.As(err, &valueErr) {
+				t.Errorf("format error = %T, want %T", err, valueErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("format = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ package client
 
 import (
 	"errors"
+	"strings"
 )
 
 var (
@@ -27,10 +28,47 @@ var (
 	errCannotDetermineEngineURL = errors.New("cannot determine engine url")
 	// ErrColumnsValuesLenMismatch occurs when trying to insert a row with a different column and value lengths.
 	ErrColumnsValuesLenMismatch = errors.New("number of columns must be equal to number of values")
-	// ErrCannotCastValueToFloat64 occurs when trying to cast any to float64 but it failed.
-	ErrCannotCastValueToFloat64 = errors.New("cannot cast value to float64")
+	// ErrColumnNotFound occurs when the table has no column with the configured name.
+	ErrColumnNotFound = errors.New("column not found")
+	// ErrEmptyKey occurs when trying to update or delete rows without a key.
+	ErrEmptyKey = errors.New("key is empty")
 	// ErrCannotCastValueToString occurs when trying to cast any to string but it failed.
 	ErrCannotCastValueToString = errors.New("cannot cast value to string")
+	// ErrCannotConvertValue occurs when a value of a query result doesn't match the type of its column.
+	ErrCannotConvertValue = errors.New("cannot convert value")
 	// ErrCannotParseTime occurs when trying to cast any to string but it failed.
 	ErrCannotParseTime = errors.New("parse time error")
 )
+
+// schemaErrorMessages are parts of the messages of the errors caused by a mismatch
+// between a query and the table schema.
+var schemaErrorMessages = []string{
+	"does not exist",
+	"doesn't exist",
+	"unknown column",
+	"missing columns",
+	"no such column",
+	"type mismatch",
+	"cannot parse",
+	"cannot convert",
+	"unable to cast",
+	"invalid input syntax",
+}
+
+// IsSchemaError checks if the error of a query is caused by a mismatch between the query and the table schema,
+// e.g. an unknown column or a value of a wrong type.
+func IsSchemaError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	message := strings.ToLower(err.Error())
+
+	for i := range schemaErrorMessages {
+		if strings.Contains(message, schemaErrorMessages[i]) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,108 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsSchemaError(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    bool
+	}{
+		{
+			name:    "unknown column",
+			message: `line 1, column 25: Column 'agee' does not exist.`,
+			want:    true,
+		},
+		{
+			name:    "unknown table",
+			message: `Relation "userz" does not exist or not authorized.`,
+			want:    true,
+		},
+		{
+			name: "missing columns of the old engine",
+			message: "Missing columns: 'agee' while processing query: 'SELECT agee FROM users', " +
+				"required columns: 'agee' 'agee'. (UNKNOWN_IDENTIFIER)",
+			want: true,
+		},
+		{
+			name:    "no such column in INSERT",
+			message: "No such column agee in table users. (NO_SUCH_COLUMN_IN_TABLE)",
+			want:    true,
+		},
+		{
+			name: "value of a wrong type",
+			message: "Cannot parse input: expected ',' before: 'abc', 'two'): (at row 1). " +
+				"(CANNOT_PARSE_INPUT_ASSERTION_FAILED)",
+			want: true,
+		},
+		{
+			name:    "type mismatch",
+			message: "Type mismatch in VALUES section. Repeat query with types_check=1. (TYPE_MISMATCH)",
+			want:    true,
+		},
+		{
+			name:    "cast of a wrong value",
+			message: `Unable to cast TEXT 'abc' to INTEGER.`,
+			want:    true,
+		},
+		{
+			name:    "invalid input of a type",
+			message: `invalid input syntax for type integer: "abc"`,
+			want:    true,
+		},
+		{
+			name:    "syntax error",
+			message: `line 1, column 8: syntax error, unexpected identifier, expecting FROM`,
+		},
+		{
+			name:    "engine not running",
+			message: `Engine my_engine is not running.`,
+		},
+		{
+			name:    "authentication",
+			message: `Authentication failed: invalid access token.`,
+		},
+		{
+			name:    "division by zero",
+			message: `Division by zero.`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the messages are returned in the bodies of the failed requests.
+			err := fmt.Errorf("run query: execute run query request: %w, 400, body:{\"error\":%q}",
+				errInValidHTTPStatusCode, tt.message)
+
+			if got := IsSchemaError(err); got != tt.want {
+				t.Errorf("is schema error = %t, want %t", got, tt.want)
+			}
+		})
+	}
+
+	if IsSchemaError(nil) {
+		t.Error("nil is a schema error")
+	}
+
+	if IsSchemaError(errors.New("context deadline exceeded")) {
+		t.Error("context error is a schema error")
+	}
+}
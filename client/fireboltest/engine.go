@@ -0,0 +1,752 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fireboltest
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// table is an in-memory table.
+type table struct {
+	name         string
+	dimension    bool
+	columns      []columnDef
+	primaryIndex []string
+	rows         [][]any
+}
+
+// resultColumns returns the columns of the table as columns of a query result.
+func (t *table) resultColumns() []resultColumn {
+	columns := make([]resultColumn, len(t.columns))
+	for i, col := range t.columns {
+		columns[i] = resultColumn{name: col.name, typ: col.typ}
+	}
+
+	return columns
+}
+
+// columnIndex returns the index of the column or -1 if the table doesn't have it.
+func (t *table) columnIndex(name string) int {
+	for i := range t.columns {
+		if t.columns[i].name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// result is a result of a query.
+type result struct {
+	columns []resultColumn
+	rows    [][]any
+}
+
+// resultColumn is a column of a query result.
+type resultColumn struct {
+	name string
+	typ  dataType
+}
+
+// database is an in-memory database able to execute the SQL subset used by the connector.
+type database struct {
+	mu     sync.Mutex
+	tables map[string]*table
+}
+
+func newDatabase() *database {
+	return &database{tables: make(map[string]*table)}
+}
+
+// exec executes an SQL statement and returns its result, which is nil for statements that don't return rows.
+func (db *database) exec(sql string) (*result, error) {
+	stmt, err := parse(sql)
+	if err != nil {
+		return nil, fmt.Errorf("syntax error: %w", err)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	switch stmt := stmt.(type) {
+	case *createTableStmt:
+		return nil, db.createTable(stmt)
+	case *alterTableStmt:
+		return nil, db.alterTable(stmt)
+	case *dropTableStmt:
+		return nil, db.dropTable(stmt)
+	case *insertStmt:
+		return nil, db.insert(stmt)
+	case *updateStmt:
+		return nil, db.update(stmt)
+	case *deleteStmt:
+		return nil, db.deleteRows(stmt)
+	case *selectStmt:
+		return db.selectRows(stmt)
+	case *describeStmt:
+		return db.describe(stmt)
+	case *showIndexesStmt:
+		return db.showIndexes(), nil
+	case *showTablesStmt:
+		return db.showTables(), nil
+	default:
+		return nil, fmt.Errorf("unsupported statement %T", stmt)
+	}
+}
+
+func (db *database) table(name string) (*table, error) {
+	t, ok := db.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("table %q does not exist", name)
+	}
+
+	return t, nil
+}
+
+func (db *database) createTable(stmt *createTableStmt) error {
+	if _, ok := db.tables[stmt.name]; ok {
+		if stmt.ifNotExists {
+			return nil
+		}
+
+		return fmt.Errorf("table %q already exists", stmt.name)
+	}
+
+	t := &table{name: stmt.name, dimension: stmt.dimension, columns: stmt.columns}
+
+	for _, col := range stmt.primaryIndex {
+		if t.columnIndex(col) < 0 {
+			return fmt.Errorf("primary index column %q does not exist", col)
+		}
+
+		t.primaryIndex = append(t.primaryIndex, col)
+	}
+
+	db.tables[stmt.name] = t
+
+	return nil
+}
+
+func (db *database) alterTable(stmt *alterTableStmt) error {
+	t, err := db.table(stmt.table)
+	if err != nil {
+		return err
+	}
+
+	if t.columnIndex(stmt.column.name) >= 0 {
+		return fmt.Errorf("column %q already exists in table %q", stmt.column.name, t.name)
+	}
+
+	// the existing rows get NULL values of the added column.
+	if !stmt.column.typ.nullable && len(t.rows) > 0 {
+		return fmt.Errorf("column %q added to a non-empty table must be nullable", stmt.column.name)
+	}
+
+	t.columns = append(t.columns, stmt.column)
+
+	for i := range t.rows {
+		t.rows[i] = append(t.rows[i], nil)
+	}
+
+	return nil
+}
+
+func (db *database) dropTable(stmt *dropTableStmt) error {
+	if _, ok := db.tables[stmt.name]; !ok && !stmt.ifExists {
+		return fmt.Errorf("table %q does not exist", stmt.name)
+	}
+
+	delete(db.tables, stmt.name)
+
+	return nil
+}
+
+func (db *database) insert(stmt *insertStmt) error {
+	t, err := db.table(stmt.table)
+	if err != nil {
+		return err
+	}
+
+	indexes := make([]int, 0, len(t.columns))
+
+	if len(stmt.columns) == 0 {
+		for i := range t.columns {
+			indexes = append(indexes, i)
+		}
+	}
+
+	for _, col := range stmt.columns {
+		idx := t.columnIndex(col)
+		if idx < 0 {
+			return fmt.Errorf("column %q does not exist in table %q", col, t.name)
+		}
+
+		indexes = append(indexes, idx)
+	}
+
+	rows := make([][]any, 0, len(stmt.rows))
+
+	for _, values := range stmt.rows {
+		if len(values) != len(indexes) {
+			return fmt.Errorf("expected %d values, got %d", len(indexes), len(values))
+		}
+
+		row := make([]any, len(t.columns))
+		for i, col := range t.columns {
+			if col.typ.nullable {
+				continue
+			}
+
+			row[i] = col.typ.zero()
+		}
+
+		for i, e := range values {
+			col := t.columns[indexes[i]]
+
+			value, err := evalConst(e)
+			if err != nil {
+				return err
+			}
+
+			if row[indexes[i]], err = coerceColumn(col, value); err != nil {
+				return err
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	// all rows are validated, so the insert is atomic.
+	t.rows = append(t.rows, rows...)
+
+	return nil
+}
+
+func (db *database) update(stmt *updateStmt) error {
+	t, err := db.table(stmt.table)
+	if err != nil {
+		return err
+	}
+
+	indexes := make([]int, len(stmt.set))
+	for i, a := range stmt.set {
+		if indexes[i] = t.columnIndex(a.column); indexes[i] < 0 {
+			return fmt.Errorf("column %q does not exist in table %q", a.column, t.name)
+		}
+	}
+
+	columns := t.resultColumns()
+	rows := make([][]any, len(t.rows))
+
+	for i, row := range t.rows {
+		rows[i] = row
+
+		ok, err := matches(stmt.where, columns, row)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		// the values are evaluated against the row before the update.
+		rows[i] = slices.Clone(row)
+
+		for j, a := range stmt.set {
+			value, err := eval(a.value, columns, row)
+			if err != nil {
+				return err
+			}
+
+			if rows[i][indexes[j]], err = coerceColumn(t.columns[indexes[j]], value); err != nil {
+				return err
+			}
+		}
+	}
+
+	// all rows are validated, so the update is atomic.
+	t.rows = rows
+
+	return nil
+}
+
+func (db *database) deleteRows(stmt *deleteStmt) error {
+	t, err := db.table(stmt.table)
+	if err != nil {
+		return err
+	}
+
+	columns := t.resultColumns()
+	rows := make([][]any, 0, len(t.rows))
+
+	for _, row := range t.rows {
+		ok, err := matches(stmt.where, columns, row)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			rows = append(rows, row)
+		}
+	}
+
+	t.rows = rows
+
+	return nil
+}
+
+func (db *database) selectRows(stmt *selectStmt) (*result, error) {
+	source, err := db.source(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := &result{columns: source.columns}
+
+	for _, row := range source.rows {
+		ok, err := matches(stmt.where, source.columns, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			filtered.rows = append(filtered.rows, row)
+		}
+	}
+
+	if hasAggregates(stmt.items) {
+		return aggregate(stmt.items, filtered)
+	}
+
+	if err = sortRows(filtered, stmt.orderBy); err != nil {
+		return nil, err
+	}
+
+	filtered.rows = paginate(filtered.rows, stmt.limit, stmt.offset)
+
+	return project(stmt.items, filtered)
+}
+
+// source returns the rows the SELECT statement reads from.
+func (db *database) source(stmt *selectStmt) (*result, error) {
+	if stmt.fromQuery != nil {
+		return db.selectRows(stmt.fromQuery)
+	}
+
+	if stmt.from == "" {
+		return &result{rows: [][]any{{}}}, nil
+	}
+
+	t, err := db.table(stmt.from)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result{columns: t.resultColumns(), rows: t.rows}, nil
+}
+
+func (db *database) describe(stmt *describeStmt) (*result, error) {
+	t, err := db.table(stmt.table)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{columns: stringColumns("table_name", "column_name", "data_type", "nullable")}
+
+	for _, col := range t.columns {
+		nullable := "0"
+		if col.typ.nullable {
+			nullable = "1"
+		}
+
+		res.rows = append(res.rows, []any{t.name, col.name, col.typ.sqlName(), nullable})
+	}
+
+	return res, nil
+}
+
+func (db *database) showIndexes() *result {
+	res := &result{columns: stringColumns("index_name", "table_name", "type", "expression")}
+
+	for _, name := range db.tableNames() {
+		t := db.tables[name]
+		if len(t.primaryIndex) == 0 {
+			continue
+		}
+
+		res.rows = append(res.rows, []any{
+			"primary_" + t.name, t.name, "primary", "[" + strings.Join(t.primaryIndex, ",") + "]",
+		})
+	}
+
+	return res
+}
+
+func (db *database) showTables() *result {
+	res := &result{columns: stringColumns("table_name", "table_type", "primary_index")}
+
+	for _, name := range db.tableNames() {
+		t := db.tables[name]
+
+		tableType := "FACT"
+		if t.dimension {
+			tableType = "DIMENSION"
+		}
+
+		res.rows = append(res.rows, []any{t.name, tableType, strings.Join(t.primaryIndex, ",")})
+	}
+
+	return res
+}
+
+// tableNames returns the sorted names of the tables.
+func (db *database) tableNames() []string {
+	names := make([]string, 0, len(db.tables))
+	for name := range db.tables {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// coerceColumn converts the value to the column's type and checks its nullability.
+func coerceColumn(col columnDef, value any) (any, error) {
+	if value == nil && !col.typ.nullable {
+		return nil, fmt.Errorf("column %q is not nullable", col.name)
+	}
+
+	converted, err := col.typ.coerce(value)
+	if err != nil {
+		return nil, fmt.Errorf("column %q: %w", col.name, err)
+	}
+
+	return converted, nil
+}
+
+// matches evaluates the WHERE predicate against a row, a nil predicate matches all rows.
+func matches(where expr, columns []resultColumn, row []any) (bool, error) {
+	if where == nil {
+		return true, nil
+	}
+
+	value, err := eval(where, columns, row)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := value.(bool)
+
+	return ok && b, nil
+}
+
+// sortRows sorts the rows by the ORDER BY items, NULL values go last.
+func sortRows(res *result, orderBy []orderItem) error {
+	if len(orderBy) == 0 {
+		return nil
+	}
+
+	keys := make([][]any, len(res.rows))
+
+	for i, row := range res.rows {
+		keys[i] = make([]any, len(orderBy))
+
+		for j, item := range orderBy {
+			value, err := eval(item.expr, res.columns, row)
+			if err != nil {
+				return err
+			}
+
+			keys[i][j] = value
+		}
+	}
+
+	var sortErr error
+
+	indexes := make([]int, len(res.rows))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	sort.SliceStable(indexes, func(a, b int) bool {
+		for j, item := range orderBy {
+			av, bv := keys[indexes[a]][j], keys[indexes[b]][j]
+
+			switch {
+			case av == nil && bv == nil:
+				continue
+			case av == nil:
+				return false
+			case bv == nil:
+				return true
+			}
+
+			cmp, err := compare(av, bv)
+			if err != nil {
+				sortErr = err
+
+				return false
+			}
+
+			if cmp != 0 {
+				return (cmp < 0) != item.desc
+			}
+		}
+
+		return false
+	})
+
+	sorted := make([][]any, len(res.rows))
+	for i, idx := range indexes {
+		sorted[i] = res.rows[idx]
+	}
+
+	res.rows = sorted
+
+	return sortErr
+}
+
+func paginate(rows [][]any, limit, offset *int64) [][]any {
+	if offset != nil {
+		if *offset >= int64(len(rows)) {
+			return nil
+		}
+
+		rows = rows[*offset:]
+	}
+
+	if limit != nil && *limit < int64(len(rows)) {
+		rows = rows[:*limit]
+	}
+
+	return rows
+}
+
+// project evaluates the SELECT list against the rows.
+func project(items []selectItem, res *result) (*result, error) {
+	projected := &result{}
+
+	for i, item := range items {
+		if item.star {
+			projected.columns = append(projected.columns, res.columns...)
+
+			continue
+		}
+
+		projected.columns = append(projected.columns, resultColumn{
+			name: itemName(item, i),
+			typ:  typeOf(item.expr, res.columns),
+		})
+	}
+
+	for _, row := range res.rows {
+		out := make([]any, 0, len(projected.columns))
+
+		for _, item := range items {
+			if item.star {
+				out = append(out, row...)
+
+				continue
+			}
+
+			value, err := eval(item.expr, res.columns, row)
+			if err != nil {
+				return nil, err
+			}
+
+			out = append(out, value)
+		}
+
+		projected.rows = append(projected.rows, out)
+	}
+
+	return projected, nil
+}
+
+func itemName(item selectItem, idx int) string {
+	if item.alias != "" {
+		return item.alias
+	}
+
+	switch e := item.expr.(type) {
+	case *columnExpr:
+		return e.name
+	case *funcExpr:
+		return strings.ToLower(e.name)
+	default:
+		return fmt.Sprintf("?column?%d", idx+1)
+	}
+}
+
+// typeOf infers the data type of the expression.
+func typeOf(e expr, columns []resultColumn) dataType {
+	switch e := e.(type) {
+	case *columnExpr:
+		for _, col := range columns {
+			if col.name == e.name {
+				return col.typ
+			}
+		}
+	case *castExpr:
+		return e.typ
+	case *funcExpr:
+		if e.name == "COUNT" {
+			return dataType{name: typeBigInt}
+		}
+
+		if len(e.args) > 0 {
+			typ := typeOf(e.args[0], columns)
+			typ.nullable = true
+
+			return typ
+		}
+	case *literalExpr:
+		return typeOfValue(e.value)
+	case *unaryExpr:
+		return typeOf(e.operand, columns)
+	case *binaryExpr:
+		switch e.op {
+		case "+", "-":
+			return typeOf(e.left, columns)
+		case "||":
+			return dataType{name: typeText}
+		}
+	}
+
+	return dataType{name: typeBoolean, nullable: true}
+}
+
+func typeOfValue(value any) dataType {
+	switch value.(type) {
+	case nil:
+		return dataType{name: typeText, nullable: true}
+	case int64:
+		return dataType{name: typeBigInt}
+	case float64:
+		return dataType{name: typeDouble}
+	case string:
+		return dataType{name: typeText}
+	case time.Time:
+		return dataType{name: typeTimestamp}
+	case *big.Rat, *big.Int:
+		return dataType{name: typeDecimal, precision: 38}
+	default:
+		return dataType{name: typeBoolean}
+	}
+}
+
+func stringColumns(names ...string) []resultColumn {
+	columns := make([]resultColumn, len(names))
+	for i, name := range names {
+		columns[i] = resultColumn{name: name, typ: dataType{name: typeText}}
+	}
+
+	return columns
+}
+
+// hasAggregates reports whether the SELECT list contains aggregate functions.
+func hasAggregates(items []selectItem) bool {
+	for _, item := range items {
+		if fn, ok := item.expr.(*funcExpr); ok && isAggregate(fn.name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isAggregate(name string) bool {
+	return name == "MIN" || name == "MAX" || name == "COUNT"
+}
+
+// aggregate computes a single row of aggregate functions over the rows.
+func aggregate(items []selectItem, res *result) (*result, error) {
+	out := &result{rows: [][]any{make([]any, len(items))}}
+
+	for i, item := range items {
+		fn, ok := item.expr.(*funcExpr)
+		if !ok || !isAggregate(fn.name) {
+			return nil, fmt.Errorf("only aggregate functions can be selected without GROUP BY")
+		}
+
+		out.columns = append(out.columns, resultColumn{name: itemName(item, i), typ: typeOf(fn, res.columns)})
+
+		value, err := aggregateValue(fn, res)
+		if err != nil {
+			return nil, err
+		}
+
+		out.rows[0][i] = value
+	}
+
+	return out, nil
+}
+
+func aggregateValue(fn *funcExpr, res *result) (any, error) {
+	if fn.name == "COUNT" && fn.star {
+		return int64(len(res.rows)), nil
+	}
+
+	if len(fn.args) != 1 {
+		return nil, fmt.Errorf("%s expects one argument", fn.name)
+	}
+
+	var (
+		acc   any
+		count int64
+	)
+
+	for _, row := range res.rows {
+		value, err := eval(fn.args[0], res.columns, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if value == nil {
+			continue
+		}
+
+		count++
+
+		if acc == nil {
+			acc = value
+
+			continue
+		}
+
+		cmp, err := compare(value, acc)
+		if err != nil {
+			return nil, err
+		}
+
+		if (fn.name == "MIN" && cmp < 0) || (fn.name == "MAX" && cmp > 0) {
+			acc = value
+		}
+	}
+
+	if fn.name == "COUNT" {
+		return count, nil
+	}
+
+	return acc, nil
+}
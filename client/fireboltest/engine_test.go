@@ -0,0 +1,179 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fireboltest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDatabase_exec(t *testing.T) {
+	t.Parallel()
+
+	setup := []string{
+		"CREATE FACT TABLE users (id INT, name TEXT NULL, created DATE NULL) PRIMARY INDEX id",
+		"INSERT INTO users VALUES (3, 'c', '2022-01-03'), (1, 'a', NULL), (2, E'b\\'s', '2022-01-02')",
+		"INSERT INTO users (id) VALUES (4)",
+	}
+
+	tests := []struct {
+		name string
+		// before holds statements executed before the query.
+		before  []string
+		query   string
+		want    [][]any
+		wantErr bool
+	}{
+		{
+			name:  "order by with limit and offset",
+			query: "SELECT id, name FROM users ORDER BY id LIMIT 2 OFFSET 1",
+			want:  [][]any{{int64(2), "b's"}, {int64(3), "c"}},
+		},
+		{
+			name:  "order by desc",
+			query: "SELECT id FROM users ORDER BY id DESC LIMIT 2",
+			want:  [][]any{{int64(4)}, {int64(3)}},
+		},
+		{
+			name:  "where with row value comparison",
+			query: "SELECT id FROM users WHERE (id, name) > (2, 'b') AND name IS NOT NULL ORDER BY id",
+			want:  [][]any{{int64(2)}, {int64(3)}},
+		},
+		{
+			name:  "null doesn't match comparisons",
+			query: "SELECT id FROM users WHERE created > '2022-01-01' ORDER BY id",
+			want:  [][]any{{int64(2)}, {int64(3)}},
+		},
+		{
+			name:  "aggregates",
+			query: "SELECT MIN(id), MAX(id), COUNT(*) FROM users",
+			want:  [][]any{{int64(1), int64(4), int64(4)}},
+		},
+		{
+			name:  "select from subquery",
+			query: `SELECT "total" FROM (SELECT id + 10 AS total, name FROM users WHERE id > 1) AS "q" ORDER BY total LIMIT 2`,
+			want:  [][]any{{int64(12)}, {int64(13)}},
+		},
+		{
+			name:  "show indexes",
+			query: "SHOW INDEXES;",
+			want:  [][]any{{"primary_users", "users", "primary", "[id]"}},
+		},
+		{
+			name:   "update",
+			before: []string{"UPDATE users SET name = name || '!', created = NULL WHERE id >= 3"},
+			query:  "SELECT id, name FROM users WHERE created IS NULL ORDER BY id",
+			want:   [][]any{{int64(1), "a"}, {int64(3), "c!"}, {int64(4), nil}},
+		},
+		{
+			name: "array with NULL elements",
+			before: []string{
+				"CREATE DIMENSION TABLE events (id INT, tags ARRAY(TEXT NULL))",
+				"INSERT INTO events VALUES (1, ['a', NULL])",
+			},
+			query: "SELECT tags FROM events",
+			want:  [][]any{{[]any{"a", nil}}},
+		},
+		{
+			name:    "NULL element of not nullable array",
+			before:  []string{"CREATE DIMENSION TABLE events (id INT, tags ARRAY(TEXT))"},
+			query:   "INSERT INTO events VALUES (1, ['a', NULL])",
+			wantErr: true,
+		},
+		{
+			name: "alter table add column",
+			before: []string{
+				"ALTER TABLE users ADD COLUMN score DOUBLE NULL",
+				"INSERT INTO users VALUES (5, 'e', NULL, 1.5)",
+			},
+			query: "SELECT id, score FROM users WHERE id >= 4 ORDER BY id",
+			want:  [][]any{{int64(4), nil}, {int64(5), 1.5}},
+		},
+		{
+			name:    "alter table add not nullable column",
+			query:   "ALTER TABLE users ADD COLUMN score DOUBLE NOT NULL",
+			wantErr: true,
+		},
+		{
+			name:   "delete",
+			before: []string{"DELETE FROM users WHERE id = 1 OR name = 'c'"},
+			query:  "SELECT id FROM users ORDER BY id",
+			want:   [][]any{{int64(2)}, {int64(4)}},
+		},
+		{
+			name:    "update of a not nullable column to null",
+			query:   "UPDATE users SET id = NULL",
+			wantErr: true,
+		},
+		{
+			name:   "show tables",
+			before: []string{"CREATE DIMENSION TABLE groups (id INT)"},
+			query:  "SHOW TABLES",
+			want:   [][]any{{"groups", "DIMENSION", ""}, {"users", "FACT", "id"}},
+		},
+		{
+			name:    "unknown column",
+			query:   "SELECT unknown FROM users",
+			wantErr: true,
+		},
+		{
+			name:    "unknown table",
+			query:   "SELECT * FROM unknown",
+			wantErr: true,
+		},
+		{
+			name:    "null into not null column",
+			query:   "INSERT INTO users (id, name) VALUES (NULL, 'e')",
+			wantErr: true,
+		},
+		{
+			name:    "value of wrong type",
+			query:   "INSERT INTO users (id, name) VALUES ('five', 'e')",
+			wantErr: true,
+		},
+		{
+			name:    "syntax error",
+			query:   "SELECT FROM WHERE",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := newDatabase()
+
+			for _, query := range append(setup, tt.before...) {
+				if _, err := db.exec(query); err != nil {
+					t.Fatalf("setup query %q: %v", query, err)
+				}
+			}
+
+			got, err := db.exec(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("exec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(got.rows, tt.want) {
+				t.Errorf("exec() got = %v, want %v", got.rows, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fireboltest
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// evalConst evaluates an expression that doesn't reference any columns.
+func evalConst(e expr) (any, error) {
+	return eval(e, nil, nil)
+}
+
+// eval evaluates an expression against a row. NULL is represented as nil.
+func eval(e expr, columns []resultColumn, row []any) (any, error) {
+	switch e := e.(type) {
+	case *literalExpr:
+		return e.value, nil
+
+	case *columnExpr:
+		for i, col := range columns {
+			if col.name == e.name {
+				return row[i], nil
+			}
+		}
+
+		return nil, fmt.Errorf("column %q does not exist", e.name)
+
+	case *arrayExpr:
+		items := make([]any, len(e.items))
+
+		for i, item := range e.items {
+			value, err := eval(item, columns, row)
+			if err != nil {
+				return nil, err
+			}
+
+			items[i] = value
+		}
+
+		return items, nil
+
+	case *castExpr:
+		value, err := eval(e.operand, columns, row)
+		if err != nil {
+			return nil, err
+		}
+
+		return cast(value, e.typ)
+
+	case *unaryExpr:
+		return evalUnary(e, columns, row)
+
+	case *binaryExpr:
+		return evalBinary(e, columns, row)
+
+	case *isNullExpr:
+		value, err := eval(e.operand, columns, row)
+		if err != nil {
+			return nil, err
+		}
+
+		return (value == nil) != e.not, nil
+
+	case *inExpr:
+		return evalIn(e, columns, row)
+
+	case *funcExpr:
+		if isAggregate(e.name) {
+			return nil, fmt.Errorf("aggregate function %s is not allowed here", e.name)
+		}
+
+		return nil, fmt.Errorf("unknown function %s", e.name)
+
+	case *tupleExpr:
+		return nil, fmt.Errorf("row values can only be compared")
+
+	default:
+		return nil, fmt.Errorf("unsupported expression %T", e)
+	}
+}
+
+// cast converts a value to a data type, unlike coerce any scalar can be cast to TEXT.
+func cast(value any, typ dataType) (any, error) {
+	if typ.name != typeText || value == nil {
+		return typ.coerce(value)
+	}
+
+	switch v := value.(type) {
+	case *big.Rat:
+		return v.RatString(), nil
+	case []any:
+		return nil, fmt.Errorf("cannot cast array to %s", typeText)
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+func evalUnary(e *unaryExpr, columns []resultColumn, row []any) (any, error) {
+	value, err := eval(e.operand, columns, row)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	if e.op == "NOT" {
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("argument of NOT must be boolean, got %T", value)
+		}
+
+		return !b, nil
+	}
+
+	switch v := value.(type) {
+	case int64:
+		return -v, nil
+	case float64:
+		return -v, nil
+	case *big.Int:
+		return new(big.Int).Neg(v), nil
+	case *big.Rat:
+		return new(big.Rat).Neg(v), nil
+	default:
+		return nil, fmt.Errorf("cannot negate %T", value)
+	}
+}
+
+func evalBinary(e *binaryExpr, columns []resultColumn, row []any) (any, error) {
+	if e.op == "AND" || e.op == "OR" {
+		return evalLogical(e, columns, row)
+	}
+
+	if lt, ok := e.left.(*tupleExpr); ok {
+		rt, ok := e.right.(*tupleExpr)
+		if !ok || len(lt.items) != len(rt.items) {
+			return nil, fmt.Errorf("row values must have the same number of elements")
+		}
+
+		return compareTuples(e.op, lt, rt, columns, row)
+	}
+
+	left, err := eval(e.left, columns, row)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := eval(e.right, columns, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	switch e.op {
+	case "+", "-":
+		return arithmetic(e.op, left, right)
+	case "||":
+		return fmt.Sprint(left) + fmt.Sprint(right), nil
+	}
+
+	cmp, err := compare(left, right)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareResult(e.op, cmp), nil
+}
+
+// evalLogical evaluates AND and OR using three-valued logic.
+func evalLogical(e *binaryExpr, columns []resultColumn, row []any) (any, error) {
+	left, err := evalBool(e.left, columns, row)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := evalBool(e.right, columns, row)
+	if err != nil {
+		return nil, err
+	}
+
+	// the operand that decides the result regardless of the other one.
+	decisive := e.op == "OR"
+
+	switch {
+	case (left != nil && *left == decisive) || (right != nil && *right == decisive):
+		return decisive, nil
+	case left == nil || right == nil:
+		return nil, nil
+	default:
+		return !decisive, nil
+	}
+}
+
+func evalBool(e expr, columns []resultColumn, row []any) (*bool, error) {
+	value, err := eval(e, columns, row)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	b, ok := value.(bool)
+	if !ok {
+		return nil, fmt.Errorf("argument of AND/OR must be boolean, got %T", value)
+	}
+
+	return &b, nil
+}
+
+// compareTuples compares row values lexicographically.
+func compareTuples(op string, left, right *tupleExpr, columns []resultColumn, row []any) (any, error) {
+	for i := range left.items {
+		lv, err := eval(left.items[i], columns, row)
+		if err != nil {
+			return nil, err
+		}
+
+		rv, err := eval(right.items[i], columns, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if lv == nil || rv == nil {
+			return nil, nil
+		}
+
+		cmp, err := compare(lv, rv)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmp != 0 {
+			return compareResult(op, cmp), nil
+		}
+	}
+
+	return compareResult(op, 0), nil
+}
+
+func compareResult(op string, cmp int) bool {
+	switch op {
+	case "=":
+		return cmp == 0
+	case "<>":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func arithmetic(op string, left, right any) (any, error) {
+	li, lok := left.(int64)
+	ri, rok := right.(int64)
+
+	if lok && rok {
+		if op == "+" {
+			return li + ri, nil
+		}
+
+		return li - ri, nil
+	}
+
+	lr, lok := toRat(left)
+	rr, rok := toRat(right)
+
+	if !lok || !rok || isString(left) || isString(right) {
+		return nil, fmt.Errorf("operator %s is not defined for %T and %T", op, left, right)
+	}
+
+	var r big.Rat
+	if op == "+" {
+		r.Add(lr, rr)
+	} else {
+		r.Sub(lr, rr)
+	}
+
+	f, _ := r.Float64()
+
+	return f, nil
+}
+
+func evalIn(e *inExpr, columns []resultColumn, row []any) (any, error) {
+	value, err := eval(e.operand, columns, row)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	var sawNull bool
+
+	for _, item := range e.list {
+		candidate, err := eval(item, columns, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if candidate == nil {
+			sawNull = true
+
+			continue
+		}
+
+		cmp, err := compare(value, candidate)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmp == 0 {
+			return !e.not, nil
+		}
+	}
+
+	if sawNull {
+		return nil, nil
+	}
+
+	return e.not, nil
+}
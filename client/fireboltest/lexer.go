@@ -0,0 +1,250 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fireboltest
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind is a kind of SQL token.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+// token is a single lexical token of an SQL statement.
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// twoCharSymbols holds symbols consisting of two characters.
+var twoCharSymbols = []string{"<=", ">=", "<>", "!=", "::", "||"}
+
+// lex splits an SQL statement into tokens.
+func lex(sql string) ([]token, error) {
+	var (
+		tokens []token
+		runes  = []rune(sql)
+	)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case hasPrefix(runes, i, "--"):
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '\'' || hasPrefix(runes, i, "E'") || hasPrefix(runes, i, "e'"):
+			escapes := r != '\''
+			if escapes {
+				i++
+			}
+
+			text, next, err := lexString(runes, i, escapes)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: text})
+			i = next
+
+		case r == '"':
+			text, next, err := lexQuotedIdent(runes, i)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenQuotedIdent, text: text})
+			i = next
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			next := lexNumber(runes, i)
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[i:next])})
+			i = next
+
+		case unicode.IsLetter(r) || r == '_':
+			next := lexIdent(runes, i)
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[i:next])})
+			i = next
+
+		default:
+			symbol, err := lexSymbol(runes, i)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenSymbol, text: symbol})
+			i += len([]rune(symbol))
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+// hasPrefix reports whether the runes starting at start begin with the prefix.
+func hasPrefix(runes []rune, start int, prefix string) bool {
+	for _, r := range prefix {
+		if start >= len(runes) || runes[start] != r {
+			return false
+		}
+
+		start++
+	}
+
+	return true
+}
+
+// lexString reads a string literal starting at the opening quote.
+// Escape strings (E'...') additionally support backslash escape sequences.
+func lexString(runes []rune, start int, escapes bool) (string, int, error) {
+	var sb strings.Builder
+
+	for i := start + 1; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '\'' && i+1 < len(runes) && runes[i+1] == '\'':
+			sb.WriteRune('\'')
+			i++
+
+		case r == '\'':
+			return sb.String(), i + 1, nil
+
+		case r == '\\' && escapes && i+1 < len(runes):
+			i++
+
+			switch runes[i] {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			case 'b':
+				sb.WriteRune('\b')
+			case 'f':
+				sb.WriteRune('\f')
+			case '0':
+				sb.WriteRune(0)
+			default:
+				sb.WriteRune(runes[i])
+			}
+
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated string literal")
+}
+
+// lexQuotedIdent reads a double-quoted identifier starting at the opening quote.
+func lexQuotedIdent(runes []rune, start int) (string, int, error) {
+	var sb strings.Builder
+
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] != '"' {
+			sb.WriteRune(runes[i])
+
+			continue
+		}
+
+		if i+1 < len(runes) && runes[i+1] == '"' {
+			sb.WriteRune('"')
+			i++
+
+			continue
+		}
+
+		if sb.Len() == 0 {
+			return "", 0, fmt.Errorf("zero-length quoted identifier")
+		}
+
+		return sb.String(), i + 1, nil
+	}
+
+	return "", 0, fmt.Errorf("unterminated quoted identifier")
+}
+
+// lexIdent returns the position right after the identifier starting at start.
+func lexIdent(runes []rune, start int) int {
+	i := start
+	for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+		i++
+	}
+
+	return i
+}
+
+// lexSymbol reads the one or two character symbol starting at start.
+func lexSymbol(runes []rune, start int) (string, error) {
+	if start+1 < len(runes) {
+		for _, s := range twoCharSymbols {
+			if string(runes[start:start+2]) == s {
+				return s, nil
+			}
+		}
+	}
+
+	symbol := string(runes[start])
+	if !strings.ContainsAny(symbol, "()[],.*=<>!:;+-/|%") {
+		return "", fmt.Errorf("unexpected character %q", runes[start])
+	}
+
+	return symbol, nil
+}
+
+// lexNumber returns the position right after the numeric literal starting at start.
+func lexNumber(runes []rune, start int) int {
+	i := start
+	for i < len(runes) && unicode.IsDigit(runes[i]) {
+		i++
+	}
+
+	if i < len(runes) && runes[i] == '.' {
+		i++
+		for i < len(runes) && unicode.IsDigit(runes[i]) {
+			i++
+		}
+	}
+
+	if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+		j := i + 1
+		if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+			j++
+		}
+
+		if j < len(runes) && unicode.IsDigit(runes[j]) {
+			i = j
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+		}
+	}
+
+	return i
+}
@@ -0,0 +1,1126 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fireboltest
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// statement is a parsed SQL statement.
+type statement any
+
+// createTableStmt is a CREATE TABLE statement.
+type createTableStmt struct {
+	name         string
+	dimension    bool
+	ifNotExists  bool
+	columns      []columnDef
+	primaryIndex []string
+}
+
+// columnDef is a column definition of a CREATE TABLE statement.
+type columnDef struct {
+	name string
+	typ  dataType
+}
+
+// alterTableStmt is an ALTER TABLE ... ADD COLUMN statement.
+type alterTableStmt struct {
+	table  string
+	column columnDef
+}
+
+// dropTableStmt is a DROP TABLE statement.
+type dropTableStmt struct {
+	name     string
+	ifExists bool
+}
+
+// insertStmt is an INSERT INTO ... VALUES statement.
+type insertStmt struct {
+	table   string
+	columns []string
+	rows    [][]expr
+}
+
+// updateStmt is an UPDATE ... SET ... WHERE statement.
+type updateStmt struct {
+	table string
+	set   []assignment
+	where expr
+}
+
+// assignment is a single column assignment of an UPDATE statement.
+type assignment struct {
+	column string
+	value  expr
+}
+
+// deleteStmt is a DELETE FROM ... WHERE statement.
+type deleteStmt struct {
+	table string
+	where expr
+}
+
+// selectStmt is a SELECT statement.
+type selectStmt struct {
+	items []selectItem
+	from  string
+	// fromQuery is a subquery the statement reads from instead of a table.
+	fromQuery *selectStmt
+	where     expr
+	orderBy   []orderItem
+	limit     *int64
+	offset    *int64
+}
+
+// selectItem is a single item of a SELECT list.
+type selectItem struct {
+	star  bool
+	expr  expr
+	alias string
+}
+
+// orderItem is a single item of an ORDER BY clause.
+type orderItem struct {
+	expr expr
+	desc bool
+}
+
+// describeStmt is a DESCRIBE statement.
+type describeStmt struct {
+	table string
+}
+
+// showIndexesStmt is a SHOW INDEXES statement.
+type showIndexesStmt struct{}
+
+// showTablesStmt is a SHOW TABLES statement.
+type showTablesStmt struct{}
+
+// expr is a parsed SQL expression.
+type expr any
+
+// literalExpr is a constant value.
+type literalExpr struct {
+	value any
+}
+
+// columnExpr is a reference to a column.
+type columnExpr struct {
+	name string
+}
+
+// binaryExpr is a binary operation, such as a comparison or a logical operator.
+type binaryExpr struct {
+	op          string
+	left, right expr
+}
+
+// unaryExpr is a unary operation, either NOT or a negation.
+type unaryExpr struct {
+	op      string
+	operand expr
+}
+
+// isNullExpr is an IS [NOT] NULL check.
+type isNullExpr struct {
+	operand expr
+	not     bool
+}
+
+// inExpr is an [NOT] IN (...) check.
+type inExpr struct {
+	operand expr
+	list    []expr
+	not     bool
+}
+
+// tupleExpr is a parenthesized list of expressions, used for row value comparisons.
+type tupleExpr struct {
+	items []expr
+}
+
+// arrayExpr is an array literal.
+type arrayExpr struct {
+	items []expr
+}
+
+// castExpr is a value cast to a data type.
+type castExpr struct {
+	operand expr
+	typ     dataType
+}
+
+// funcExpr is a function call.
+type funcExpr struct {
+	name string
+	args []expr
+	star bool
+}
+
+// parser is a recursive descent parser of the SQL subset supported by the fake engine.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse parses a single SQL statement.
+func parse(sql string) (statement, error) {
+	tokens, err := lex(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	stmt, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+
+	p.acceptSymbol(";")
+
+	if p.peek().kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q after the end of the statement", p.peek().text)
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) statement() (statement, error) {
+	switch {
+	case p.acceptKeyword("CREATE"):
+		return p.createTable()
+	case p.acceptKeyword("ALTER"):
+		return p.alterTable()
+	case p.acceptKeyword("DROP"):
+		return p.dropTable()
+	case p.acceptKeyword("INSERT"):
+		return p.insert()
+	case p.acceptKeyword("UPDATE"):
+		return p.update()
+	case p.acceptKeyword("DELETE"):
+		return p.deleteFrom()
+	case p.acceptKeyword("SELECT"):
+		return p.selectBody()
+	case p.acceptKeyword("DESCRIBE"):
+		table, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+
+		return &describeStmt{table: table}, nil
+	case p.acceptKeyword("SHOW"):
+		if p.acceptKeyword("TABLES") {
+			return &showTablesStmt{}, nil
+		}
+
+		if err := p.expectKeyword("INDEXES"); err != nil {
+			return nil, err
+		}
+
+		return &showIndexesStmt{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported statement starting with %q", p.peek().text)
+	}
+}
+
+func (p *parser) createTable() (statement, error) {
+	stmt := &createTableStmt{}
+
+	if !p.acceptKeyword("FACT") {
+		stmt.dimension = p.acceptKeyword("DIMENSION")
+	}
+
+	if err := p.expectKeyword("TABLE"); err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("IF") {
+		if err := p.expectKeywords("NOT", "EXISTS"); err != nil {
+			return nil, err
+		}
+
+		stmt.ifNotExists = true
+	}
+
+	var err error
+	if stmt.name, err = p.identifier(); err != nil {
+		return nil, err
+	}
+
+	if err = p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+
+	for {
+		var col columnDef
+		if col, err = p.columnDef(); err != nil {
+			return nil, err
+		}
+
+		stmt.columns = append(stmt.columns, col)
+
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	if err = p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("PRIMARY") {
+		if err = p.expectKeyword("INDEX"); err != nil {
+			return nil, err
+		}
+
+		if stmt.primaryIndex, err = p.identifierList(); err != nil {
+			return nil, err
+		}
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) columnDef() (columnDef, error) {
+	var (
+		col columnDef
+		err error
+	)
+
+	if col.name, err = p.identifier(); err != nil {
+		return columnDef{}, err
+	}
+
+	if col.typ, err = p.dataType(); err != nil {
+		return columnDef{}, err
+	}
+
+	switch {
+	case p.acceptKeyword("NULL"):
+		col.typ.nullable = true
+	case p.acceptKeyword("NOT"):
+		if err = p.expectKeyword("NULL"); err != nil {
+			return columnDef{}, err
+		}
+	}
+
+	return col, nil
+}
+
+func (p *parser) alterTable() (statement, error) {
+	if err := p.expectKeyword("TABLE"); err != nil {
+		return nil, err
+	}
+
+	stmt := &alterTableStmt{}
+
+	var err error
+	if stmt.table, err = p.identifier(); err != nil {
+		return nil, err
+	}
+
+	if err = p.expectKeywords("ADD", "COLUMN"); err != nil {
+		return nil, err
+	}
+
+	if stmt.column, err = p.columnDef(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) dropTable() (statement, error) {
+	if err := p.expectKeyword("TABLE"); err != nil {
+		return nil, err
+	}
+
+	stmt := &dropTableStmt{}
+	if p.acceptKeyword("IF") {
+		if err := p.expectKeyword("EXISTS"); err != nil {
+			return nil, err
+		}
+
+		stmt.ifExists = true
+	}
+
+	var err error
+	stmt.name, err = p.identifier()
+
+	return stmt, err
+}
+
+func (p *parser) insert() (statement, error) {
+	if err := p.expectKeyword("INTO"); err != nil {
+		return nil, err
+	}
+
+	stmt := &insertStmt{}
+
+	var err error
+	if stmt.table, err = p.identifier(); err != nil {
+		return nil, err
+	}
+
+	if p.acceptSymbol("(") {
+		if stmt.columns, err = p.identifierList(); err != nil {
+			return nil, err
+		}
+
+		if err = p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = p.expectKeyword("VALUES"); err != nil {
+		return nil, err
+	}
+
+	for {
+		if err = p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+
+		row, err := p.exprList()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+
+		stmt.rows = append(stmt.rows, row)
+
+		if !p.acceptSymbol(",") {
+			return stmt, nil
+		}
+	}
+}
+
+func (p *parser) update() (statement, error) {
+	stmt := &updateStmt{}
+
+	var err error
+	if stmt.table, err = p.identifier(); err != nil {
+		return nil, err
+	}
+
+	if err = p.expectKeyword("SET"); err != nil {
+		return nil, err
+	}
+
+	for {
+		var a assignment
+		if a.column, err = p.identifier(); err != nil {
+			return nil, err
+		}
+
+		if err = p.expectSymbol("="); err != nil {
+			return nil, err
+		}
+
+		if a.value, err = p.expr(); err != nil {
+			return nil, err
+		}
+
+		stmt.set = append(stmt.set, a)
+
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	if p.acceptKeyword("WHERE") {
+		if stmt.where, err = p.expr(); err != nil {
+			return nil, err
+		}
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) deleteFrom() (statement, error) {
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+
+	stmt := &deleteStmt{}
+
+	var err error
+	if stmt.table, err = p.identifier(); err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("WHERE") {
+		if stmt.where, err = p.expr(); err != nil {
+			return nil, err
+		}
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) selectBody() (*selectStmt, error) {
+	stmt := &selectStmt{}
+
+	var err error
+	if stmt.items, err = p.selectItems(); err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("FROM") {
+		if err = p.from(stmt); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.acceptKeyword("WHERE") {
+		if stmt.where, err = p.expr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.acceptKeyword("ORDER") {
+		if stmt.orderBy, err = p.orderBy(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		switch {
+		case p.acceptKeyword("LIMIT"):
+			if stmt.limit, err = p.integer(); err != nil {
+				return nil, err
+			}
+		case p.acceptKeyword("OFFSET"):
+			if stmt.offset, err = p.integer(); err != nil {
+				return nil, err
+			}
+		default:
+			return stmt, nil
+		}
+	}
+}
+
+// selectItems parses the comma-separated list of the selected expressions with optional aliases.
+func (p *parser) selectItems() ([]selectItem, error) {
+	var items []selectItem
+
+	for {
+		item := selectItem{}
+		if p.acceptSymbol("*") {
+			item.star = true
+		} else {
+			var err error
+			if item.expr, err = p.expr(); err != nil {
+				return nil, err
+			}
+
+			if p.acceptKeyword("AS") || p.peek().kind == tokenQuotedIdent ||
+				(p.peek().kind == tokenIdent && !isReserved(p.peek().text)) {
+				if item.alias, err = p.identifier(); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		items = append(items, item)
+
+		if !p.acceptSymbol(",") {
+			return items, nil
+		}
+	}
+}
+
+// orderBy parses the BY keyword and the comma-separated list of the ordering expressions following ORDER.
+func (p *parser) orderBy() ([]orderItem, error) {
+	if err := p.expectKeyword("BY"); err != nil {
+		return nil, err
+	}
+
+	var items []orderItem
+
+	for {
+		item := orderItem{}
+
+		var err error
+		if item.expr, err = p.expr(); err != nil {
+			return nil, err
+		}
+
+		if p.acceptKeyword("DESC") {
+			item.desc = true
+		} else {
+			p.acceptKeyword("ASC")
+		}
+
+		items = append(items, item)
+
+		if !p.acceptSymbol(",") {
+			return items, nil
+		}
+	}
+}
+
+// from parses the table or the subquery with an optional alias the SELECT statement reads from.
+func (p *parser) from(stmt *selectStmt) error {
+	if !p.acceptSymbol("(") {
+		var err error
+		stmt.from, err = p.identifier()
+
+		return err
+	}
+
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return err
+	}
+
+	subquery, err := p.selectBody()
+	if err != nil {
+		return err
+	}
+
+	if err = p.expectSymbol(")"); err != nil {
+		return err
+	}
+
+	stmt.fromQuery = subquery
+
+	// the alias isn't used, since the columns are referenced without the table name.
+	if p.acceptKeyword("AS") || p.peek().kind == tokenQuotedIdent ||
+		(p.peek().kind == tokenIdent && !isReserved(p.peek().text)) {
+		if _, err = p.identifier(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dataType parses a column data type, such as INT, DECIMAL(38, 2) or ARRAY(TEXT).
+func (p *parser) dataType() (dataType, error) {
+	tok := p.next()
+	if tok.kind != tokenIdent {
+		return dataType{}, fmt.Errorf("expected data type, got %q", tok.text)
+	}
+
+	name := strings.ToUpper(tok.text)
+	if name == "DOUBLE" {
+		p.acceptKeyword("PRECISION")
+	}
+
+	var args []string
+
+	if p.acceptSymbol("(") {
+		if name == "ARRAY" {
+			elem, err := p.dataType()
+			if err != nil {
+				return dataType{}, err
+			}
+
+			elem.nullable = p.acceptKeyword("NULL")
+
+			if err = p.expectSymbol(")"); err != nil {
+				return dataType{}, err
+			}
+
+			return dataType{name: typeArray, elem: &elem}, nil
+		}
+
+		for {
+			tok := p.next()
+			if tok.kind != tokenNumber {
+				return dataType{}, fmt.Errorf("expected type argument, got %q", tok.text)
+			}
+
+			args = append(args, tok.text)
+
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+
+		if err := p.expectSymbol(")"); err != nil {
+			return dataType{}, err
+		}
+	}
+
+	return newDataType(name, args)
+}
+
+func (p *parser) expr() (expr, error) {
+	return p.orExpr()
+}
+
+func (p *parser) orExpr() (expr, error) {
+	left, err := p.andExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptKeyword("OR") {
+		right, err := p.andExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryExpr{op: "OR", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) andExpr() (expr, error) {
+	left, err := p.notExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptKeyword("AND") {
+		right, err := p.notExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryExpr{op: "AND", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) notExpr() (expr, error) {
+	if p.acceptKeyword("NOT") {
+		operand, err := p.notExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		return &unaryExpr{op: "NOT", operand: operand}, nil
+	}
+
+	return p.comparison()
+}
+
+func (p *parser) comparison() (expr, error) {
+	left, err := p.additive()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.acceptKeyword("IS"):
+		not := p.acceptKeyword("NOT")
+		if err = p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+
+		return &isNullExpr{operand: left, not: not}, nil
+
+	case p.peekKeyword("NOT") || p.peekKeyword("IN"):
+		not := p.acceptKeyword("NOT")
+		if err = p.expectKeyword("IN"); err != nil {
+			return nil, err
+		}
+
+		if err = p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+
+		list, err := p.exprList()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+
+		return &inExpr{operand: left, list: list, not: not}, nil
+	}
+
+	for _, op := range []string{"=", "<>", "!=", "<=", ">=", "<", ">"} {
+		if p.acceptSymbol(op) {
+			right, err := p.additive()
+			if err != nil {
+				return nil, err
+			}
+
+			if op == "!=" {
+				op = "<>"
+			}
+
+			return &binaryExpr{op: op, left: left, right: right}, nil
+		}
+	}
+
+	return left, nil
+}
+
+func (p *parser) additive() (expr, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var op string
+
+		switch {
+		case p.acceptSymbol("+"):
+			op = "+"
+		case p.acceptSymbol("-"):
+			op = "-"
+		case p.acceptSymbol("||"):
+			op = "||"
+		default:
+			return left, nil
+		}
+
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryExpr{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) unary() (expr, error) {
+	if p.acceptSymbol("-") {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &unaryExpr{op: "-", operand: operand}, nil
+	}
+
+	operand, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptSymbol("::") {
+		typ, err := p.dataType()
+		if err != nil {
+			return nil, err
+		}
+
+		operand = &castExpr{operand: operand, typ: typ}
+	}
+
+	return operand, nil
+}
+
+func (p *parser) primary() (expr, error) {
+	tok := p.peek()
+
+	switch tok.kind {
+	case tokenNumber:
+		p.pos++
+
+		return parseNumber(tok.text)
+
+	case tokenString:
+		p.pos++
+
+		return &literalExpr{value: tok.text}, nil
+
+	case tokenQuotedIdent:
+		return p.columnRef()
+
+	case tokenIdent:
+		return p.identExpr()
+
+	case tokenSymbol:
+		switch tok.text {
+		case "(":
+			p.pos++
+
+			items, err := p.exprList()
+			if err != nil {
+				return nil, err
+			}
+
+			if err = p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+
+			if len(items) == 1 {
+				return items[0], nil
+			}
+
+			return &tupleExpr{items: items}, nil
+
+		case "[":
+			p.pos++
+
+			arr := &arrayExpr{}
+			if p.acceptSymbol("]") {
+				return arr, nil
+			}
+
+			var err error
+			if arr.items, err = p.exprList(); err != nil {
+				return nil, err
+			}
+
+			return arr, p.expectSymbol("]")
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected %q in expression", tok.text)
+}
+
+// identExpr parses an expression starting with a bare identifier:
+// a keyword literal, a function call, a CAST or a column reference.
+func (p *parser) identExpr() (expr, error) {
+	switch word := strings.ToUpper(p.peek().text); word {
+	case "NULL":
+		p.pos++
+
+		return &literalExpr{value: nil}, nil
+	case "TRUE", "FALSE":
+		p.pos++
+
+		return &literalExpr{value: word == "TRUE"}, nil
+	case "CAST":
+		p.pos++
+
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+
+		operand, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = p.expectKeyword("AS"); err != nil {
+			return nil, err
+		}
+
+		typ, err := p.dataType()
+		if err != nil {
+			return nil, err
+		}
+
+		return &castExpr{operand: operand, typ: typ}, p.expectSymbol(")")
+	}
+
+	if p.tokens[p.pos+1].kind == tokenSymbol && p.tokens[p.pos+1].text == "(" {
+		fn := &funcExpr{name: strings.ToUpper(p.next().text)}
+		p.pos++
+
+		switch {
+		case p.acceptSymbol("*"):
+			fn.star = true
+		case p.peek().kind == tokenSymbol && p.peek().text == ")":
+		default:
+			var err error
+			if fn.args, err = p.exprList(); err != nil {
+				return nil, err
+			}
+		}
+
+		return fn, p.expectSymbol(")")
+	}
+
+	return p.columnRef()
+}
+
+// columnRef parses a possibly qualified column reference, the qualifier is ignored.
+func (p *parser) columnRef() (expr, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptSymbol(".") {
+		if name, err = p.identifier(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &columnExpr{name: name}, nil
+}
+
+func (p *parser) exprList() ([]expr, error) {
+	var list []expr
+
+	for {
+		e, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, e)
+
+		if !p.acceptSymbol(",") {
+			return list, nil
+		}
+	}
+}
+
+func (p *parser) identifierList() ([]string, error) {
+	var list []string
+
+	for {
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, name)
+
+		if !p.acceptSymbol(",") {
+			return list, nil
+		}
+	}
+}
+
+// identifier parses an identifier. Unquoted identifiers are case-insensitive and are folded to lower case.
+func (p *parser) identifier() (string, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenQuotedIdent:
+		return tok.text, nil
+	case tokenIdent:
+		return strings.ToLower(tok.text), nil
+	default:
+		return "", fmt.Errorf("expected identifier, got %q", tok.text)
+	}
+}
+
+func (p *parser) integer() (*int64, error) {
+	tok := p.next()
+	if tok.kind != tokenNumber {
+		return nil, fmt.Errorf("expected integer, got %q", tok.text)
+	}
+
+	n, err := strconv.ParseInt(tok.text, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse integer %q: %w", tok.text, err)
+	}
+
+	return &n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) peekKeyword(keyword string) bool {
+	tok := p.peek()
+
+	return tok.kind == tokenIdent && strings.EqualFold(tok.text, keyword)
+}
+
+func (p *parser) acceptKeyword(keyword string) bool {
+	if p.peekKeyword(keyword) {
+		p.pos++
+
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return fmt.Errorf("expected %s, got %q", keyword, p.peek().text)
+	}
+
+	return nil
+}
+
+func (p *parser) expectKeywords(keywords ...string) error {
+	for _, keyword := range keywords {
+		if err := p.expectKeyword(keyword); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) acceptSymbol(symbol string) bool {
+	tok := p.peek()
+	if tok.kind == tokenSymbol && tok.text == symbol {
+		p.pos++
+
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return fmt.Errorf("expected %q, got %q", symbol, p.peek().text)
+	}
+
+	return nil
+}
+
+// parseNumber parses a numeric literal into int64, *big.Int for integers out of the int64 range, or float64.
+func parseNumber(text string) (expr, error) {
+	if !strings.ContainsAny(text, ".eE") {
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return &literalExpr{value: n}, nil
+		}
+
+		n, ok := new(big.Int).SetString(text, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %q", text)
+		}
+
+		return &literalExpr{value: n}, nil
+	}
+
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q: %w", text, err)
+	}
+
+	return &literalExpr{value: f}, nil
+}
+
+// isReserved reports whether a bare word can't be used as an alias of a SELECT item.
+func isReserved(word string) bool {
+	switch strings.ToUpper(word) {
+	case "FROM", "WHERE", "ORDER", "LIMIT", "OFFSET", "GROUP", "UNION", "AND", "OR", "NOT", "AS", "IS", "IN":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,384 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fireboltest provides an in-process fake of the Firebolt API and engine for tests.
+//
+// The fake implements the authentication, account and engine discovery routes used by the client
+// and an engine query endpoint backed by an in-memory database, which supports
+// the SQL subset the connector issues: CREATE TABLE, ALTER TABLE ADD COLUMN, DROP TABLE, INSERT, UPDATE, DELETE,
+// SELECT from a table or a subquery with WHERE, ORDER BY, LIMIT and OFFSET, DESCRIBE, SHOW INDEXES and SHOW TABLES.
+package fireboltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials and names accepted by the fake server.
+const (
+	Email        = "user@example.com"
+	Password     = "password"
+	ClientID     = "client-id"
+	ClientSecret = "client-secret"
+	AccountName  = "account"
+	AccountID    = "account-id"
+	EngineName   = "engine"
+	EngineID     = "engine-id"
+	DB           = "db"
+)
+
+const (
+	engineRunningStatus = "ENGINE_STATUS_RUNNING_REVISION_SERVING"
+
+	accountsPathPrefix = "/core/v1/accounts/"
+	// tokenLifetime is the lifetime of access tokens reported to clients, in seconds.
+	tokenLifetime = 86400
+)
+
+// Server is a fake Firebolt server, it serves both the API and the engine endpoint.
+type Server struct {
+	// URL is the base URL of the server, used as the API URL and as the engine endpoint.
+	URL string
+
+	srv *httptest.Server
+
+	mu            sync.Mutex
+	databases     map[string]*database
+	accessTokens  map[string]struct{}
+	refreshTokens map[string]struct{}
+	tokenSeq      int
+}
+
+// NewServer starts and returns a new fake Firebolt server.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		databases:     make(map[string]*database),
+		accessTokens:  make(map[string]struct{}),
+		refreshTokens: make(map[string]struct{}),
+	}
+
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+
+	return s
+}
+
+// Close shuts down the server and blocks until all outstanding requests on this server have completed.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// ExpireTokens invalidates all issued access tokens, refresh tokens stay valid.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accessTokens = make(map[string]struct{})
+}
+
+// Exec executes an SQL statement against the database with the given name,
+// bypassing the HTTP API. It's intended for preparing test data.
+func (s *Server) Exec(dbName, query string) error {
+	_, err := s.database(dbName).exec(query)
+
+	return err
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	switch {
+	case path == "/auth/v1/login" && r.Method == http.MethodPost:
+		s.login(w, r)
+	case path == "/auth/v1/refresh" && r.Method == http.MethodPost:
+		s.refresh(w, r)
+	case path == "/auth/v1/token" && r.Method == http.MethodPost:
+		s.serviceAccountToken(w, r)
+	case !s.authorized(r):
+		writeError(w, http.StatusUnauthorized, "invalid or expired access token")
+	case path == "/iam/v2/accounts:getIdByName" && r.Method == http.MethodGet:
+		s.accountIDByName(w, r)
+	case strings.HasPrefix(path, accountsPathPrefix):
+		s.engines(w, r)
+	case path == "/" && r.Method == http.MethodPost:
+		s.query(w, r)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("route %s %s not found", r.Method, path))
+	}
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if req.Username != Email || req.Password != Password {
+		writeError(w, http.StatusForbidden, "wrong username or password")
+
+		return
+	}
+
+	writeJSON(w, s.issueTokens(true))
+}
+
+func (s *Server) refresh(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	s.mu.Lock()
+	_, ok := s.refreshTokens[req.RefreshToken]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusForbidden, "invalid refresh token")
+
+		return
+	}
+
+	writeJSON(w, s.issueTokens(false))
+}
+
+func (s *Server) serviceAccountToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if r.PostForm.Get("grant_type") != "client_credentials" ||
+		r.PostForm.Get("client_id") != ClientID || r.PostForm.Get("client_secret") != ClientSecret {
+		writeError(w, http.StatusForbidden, "invalid client credentials")
+
+		return
+	}
+
+	writeJSON(w, s.issueTokens(false))
+}
+
+// issueTokens creates a new access token, and optionally a refresh token, and returns the login response.
+func (s *Server) issueTokens(withRefreshToken bool) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokenSeq++
+
+	accessToken := fmt.Sprintf("access-token-%d", s.tokenSeq)
+	s.accessTokens[accessToken] = struct{}{}
+
+	resp := map[string]any{
+		"access_token": accessToken,
+		"expires_in":   tokenLifetime,
+		"scope":        "offline_access",
+		"token_type":   "Bearer",
+	}
+
+	if withRefreshToken {
+		refreshToken := fmt.Sprintf("refresh-token-%d", s.tokenSeq)
+		s.refreshTokens[refreshToken] = struct{}{}
+		resp["refresh_token"] = refreshToken
+	}
+
+	return resp
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok = s.accessTokens[token]
+
+	return ok
+}
+
+func (s *Server) accountIDByName(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("account_name") != AccountName {
+		writeError(w, http.StatusNotFound, "account not found")
+
+		return
+	}
+
+	writeJSON(w, map[string]any{"account_id": AccountID})
+}
+
+// engines serves the routes under /core/v1/accounts/{account_id}/.
+func (s *Server) engines(w http.ResponseWriter, r *http.Request) {
+	accountID, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, accountsPathPrefix), "/")
+	if accountID != AccountID {
+		writeError(w, http.StatusNotFound, "account not found")
+
+		return
+	}
+
+	engine := map[string]any{"engine": map[string]any{"current_status": engineRunningStatus}}
+
+	switch {
+	case rest == "engines:getIdByName" && r.Method == http.MethodGet:
+		if r.URL.Query().Get("engine_name") != EngineName {
+			writeError(w, http.StatusNotFound, "engine not found")
+
+			return
+		}
+
+		writeJSON(w, map[string]any{"engine_id": map[string]any{"account_id": AccountID, "engine_id": EngineID}})
+
+	case rest == "engines" && r.Method == http.MethodGet:
+		edges := []any{}
+		if strings.Contains(EngineName, r.URL.Query().Get("filter.name_contains")) {
+			edges = append(edges, map[string]any{"node": map[string]any{"name": EngineName, "endpoint": s.URL}})
+		}
+
+		writeJSON(w, map[string]any{"edges": edges})
+
+	case rest == "engines/"+EngineID && r.Method == http.MethodGet,
+		rest == "engines/"+EngineID+":start" && r.Method == http.MethodPost:
+		writeJSON(w, engine)
+
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("route %s %s not found", r.Method, r.URL.Path))
+	}
+}
+
+// query executes the SQL statement from the request body against the database from the query string.
+func (s *Server) query(w http.ResponseWriter, r *http.Request) {
+	dbName := r.URL.Query().Get("database")
+	if dbName == "" {
+		writeError(w, http.StatusBadRequest, "database is not specified")
+
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	start := time.Now()
+
+	res, err := s.database(dbName).exec(string(body))
+	if err != nil {
+		// the real engine responds with a server error, but that would make the client retry the query.
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	// statements that don't return rows have an empty response body.
+	if res == nil {
+		return
+	}
+
+	writeJSON(w, newQueryResponse(res, time.Since(start)))
+}
+
+// database returns the database with the given name, creating it if it doesn't exist.
+func (s *Server) database(name string) *database {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	db, ok := s.databases[name]
+	if !ok {
+		db = newDatabase()
+		s.databases[name] = db
+	}
+
+	return db
+}
+
+// queryResponse is a response of the query endpoint.
+type queryResponse struct {
+	Meta       []queryResponseMeta `json:"meta"`
+	Data       []map[string]any    `json:"data"`
+	Rows       int                 `json:"rows"`
+	Statistics queryStatistics     `json:"statistics"`
+}
+
+type queryResponseMeta struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type queryStatistics struct {
+	Elapsed   float64 `json:"elapsed"`
+	RowsRead  int     `json:"rows_read"`
+	BytesRead int     `json:"bytes_read"`
+}
+
+func newQueryResponse(res *result, elapsed time.Duration) queryResponse {
+	resp := queryResponse{
+		Meta: make([]queryResponseMeta, len(res.columns)),
+		Data: make([]map[string]any, len(res.rows)),
+		Rows: len(res.rows),
+		Statistics: queryStatistics{
+			Elapsed:  elapsed.Seconds(),
+			RowsRead: len(res.rows),
+		},
+	}
+
+	for i, col := range res.columns {
+		resp.Meta[i] = queryResponseMeta{Name: col.name, Type: col.typ.metaName()}
+	}
+
+	for i, row := range res.rows {
+		resp.Data[i] = make(map[string]any, len(res.columns))
+
+		for j, col := range res.columns {
+			resp.Data[i][col.name] = col.typ.format(row[j])
+		}
+	}
+
+	return resp
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	_ = json.NewEncoder(w).Encode(map[string]any{"error": http.StatusText(code), "message": message})
+}
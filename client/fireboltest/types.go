@@ -0,0 +1,407 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fireboltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// canonical names of the supported data types.
+const (
+	typeInt       = "INT"
+	typeBigInt    = "BIGINT"
+	typeText      = "TEXT"
+	typeDate      = "DATE"
+	typeTimestamp = "TIMESTAMP"
+	typeBoolean   = "BOOLEAN"
+	typeReal      = "REAL"
+	typeDouble    = "DOUBLE"
+	typeDecimal   = "DECIMAL"
+	typeArray     = "ARRAY"
+)
+
+// typeAliases maps names of data types to their canonical names.
+var typeAliases = map[string]string{
+	"INT":       typeInt,
+	"INTEGER":   typeInt,
+	"INT4":      typeInt,
+	"BIGINT":    typeBigInt,
+	"LONG":      typeBigInt,
+	"INT8":      typeBigInt,
+	"TEXT":      typeText,
+	"STRING":    typeText,
+	"VARCHAR":   typeText,
+	"DATE":      typeDate,
+	"TIMESTAMP": typeTimestamp,
+	"DATETIME":  typeTimestamp,
+	"BOOLEAN":   typeBoolean,
+	"BOOL":      typeBoolean,
+	"REAL":      typeReal,
+	"FLOAT":     typeReal,
+	"FLOAT4":    typeReal,
+	"DOUBLE":    typeDouble,
+	"FLOAT8":    typeDouble,
+	"DECIMAL":   typeDecimal,
+	"NUMERIC":   typeDecimal,
+}
+
+// metaNames maps canonical names of data types to the names the engine uses in query result metadata.
+var metaNames = map[string]string{
+	typeInt:       "Int32",
+	typeBigInt:    "Int64",
+	typeText:      "String",
+	typeDate:      "Date",
+	typeTimestamp: "DateTime",
+	typeBoolean:   "UInt8",
+	typeReal:      "Float32",
+	typeDouble:    "Float64",
+}
+
+// timeLayouts are the layouts accepted for date and timestamp values.
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 MST",
+}
+
+// dataType is a column data type.
+type dataType struct {
+	name      string
+	precision int
+	scale     int
+	elem      *dataType
+	nullable  bool
+}
+
+// newDataType returns a data type by its name and arguments.
+func newDataType(name string, args []string) (dataType, error) {
+	canonical, ok := typeAliases[strings.ToUpper(name)]
+	if !ok {
+		return dataType{}, fmt.Errorf("unknown data type %q", name)
+	}
+
+	typ := dataType{name: canonical}
+
+	if canonical == typeDecimal {
+		typ.precision, typ.scale = 38, 0
+
+		if len(args) > 0 {
+			typ.precision, _ = strconv.Atoi(args[0])
+		}
+
+		if len(args) > 1 {
+			typ.scale, _ = strconv.Atoi(args[1])
+		}
+	}
+
+	return typ, nil
+}
+
+// sqlName returns the name of the data type as it is shown by DESCRIBE.
+func (t dataType) sqlName() string {
+	switch t.name {
+	case typeDecimal:
+		return fmt.Sprintf("DECIMAL(%d, %d)", t.precision, t.scale)
+	case typeArray:
+		if t.elem.nullable {
+			return fmt.Sprintf("ARRAY(%s NULL)", t.elem.sqlName())
+		}
+
+		return fmt.Sprintf("ARRAY(%s)", t.elem.sqlName())
+	default:
+		return t.name
+	}
+}
+
+// metaName returns the name of the data type as it is shown in query result metadata.
+func (t dataType) metaName() string {
+	var name string
+
+	switch t.name {
+	case typeDecimal:
+		name = fmt.Sprintf("Decimal(%d, %d)", t.precision, t.scale)
+	case typeArray:
+		name = fmt.Sprintf("Array(%s)", t.elem.metaName())
+	default:
+		name = metaNames[t.name]
+	}
+
+	if t.nullable {
+		return fmt.Sprintf("Nullable(%s)", name)
+	}
+
+	return name
+}
+
+// zero returns the default value used for omitted NOT NULL columns.
+func (t dataType) zero() any {
+	switch t.name {
+	case typeInt, typeBigInt:
+		return int64(0)
+	case typeText:
+		return ""
+	case typeDate, typeTimestamp:
+		return time.Unix(0, 0).UTC()
+	case typeBoolean:
+		return false
+	case typeReal, typeDouble:
+		return float64(0)
+	case typeDecimal:
+		return new(big.Rat)
+	default:
+		return []any{}
+	}
+}
+
+// coerce converts a value to the internal representation of the data type.
+func (t dataType) coerce(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch t.name {
+	case typeInt, typeBigInt:
+		return t.coerceInteger(value)
+	case typeText:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case typeDate, typeTimestamp:
+		tm, err := toTime(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if t.name == typeDate {
+			return time.Date(tm.Year(), tm.Month(), tm.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+
+		return tm.UTC(), nil
+	case typeBoolean:
+		return coerceBoolean(value)
+	case typeReal, typeDouble:
+		if r, ok := toRat(value); ok {
+			f, _ := r.Float64()
+
+			return f, nil
+		}
+	case typeDecimal:
+		if r, ok := toRat(value); ok {
+			return r, nil
+		}
+	case typeArray:
+		return t.coerceArray(value)
+	}
+
+	return nil, fmt.Errorf("cannot convert %v (%T) to %s", value, value, t.sqlName())
+}
+
+func (t dataType) coerceInteger(value any) (any, error) {
+	r, ok := toRat(value)
+	if !ok || !r.IsInt() {
+		return nil, fmt.Errorf("cannot convert %v (%T) to %s", value, value, t.sqlName())
+	}
+
+	n := r.Num()
+	if !n.IsInt64() {
+		return nil, fmt.Errorf("value %s is out of range for %s", n, t.sqlName())
+	}
+
+	if t.name == typeInt && (n.Int64() > math.MaxInt32 || n.Int64() < math.MinInt32) {
+		return nil, fmt.Errorf("value %s is out of range for %s", n, t.sqlName())
+	}
+
+	return n.Int64(), nil
+}
+
+func (t dataType) coerceArray(value any) (any, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot convert %v (%T) to %s", value, value, t.sqlName())
+	}
+
+	result := make([]any, len(items))
+
+	for i, item := range items {
+		if item == nil && !t.elem.nullable {
+			return nil, fmt.Errorf("NULL element of %s", t.sqlName())
+		}
+
+		v, err := t.elem.coerce(item)
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = v
+	}
+
+	return result, nil
+}
+
+// format converts a value in the internal representation to the one returned in query results.
+func (t dataType) format(value any) any {
+	if value == nil {
+		return nil
+	}
+
+	switch t.name {
+	case typeDate:
+		return value.(time.Time).Format("2006-01-02")
+	case typeTimestamp:
+		return value.(time.Time).Format("2006-01-02 15:04:05.999999")
+	case typeBoolean:
+		if value.(bool) {
+			return 1
+		}
+
+		return 0
+	case typeDecimal:
+		return json.Number(value.(*big.Rat).FloatString(t.scale))
+	case typeArray:
+		items := value.([]any)
+		result := make([]any, len(items))
+
+		for i, item := range items {
+			result[i] = t.elem.format(item)
+		}
+
+		return result
+	default:
+		return value
+	}
+}
+
+func coerceBoolean(value any) (any, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case int64:
+		if v == 0 || v == 1 {
+			return v == 1, nil
+		}
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot convert %v (%T) to %s", value, value, typeBoolean)
+}
+
+// toRat converts a numeric value, or a string containing a number, to *big.Rat.
+func toRat(value any) (*big.Rat, bool) {
+	switch v := value.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(v), true
+	case *big.Int:
+		return new(big.Rat).SetInt(v), true
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, false
+		}
+
+		return new(big.Rat).SetFloat64(v), true
+	case *big.Rat:
+		return v, true
+	case string:
+		return new(big.Rat).SetString(strings.TrimSpace(v))
+	default:
+		return nil, false
+	}
+}
+
+// toTime converts a time.Time or a string in one of the supported layouts to time.Time.
+func toTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		for _, layout := range timeLayouts {
+			if tm, err := time.Parse(layout, v); err == nil {
+				return tm, nil
+			}
+		}
+
+		return time.Time{}, fmt.Errorf("cannot parse %q as date or timestamp", v)
+	default:
+		return time.Time{}, fmt.Errorf("cannot convert %v (%T) to date or timestamp", value, value)
+	}
+}
+
+// compare compares two non-NULL values and returns -1, 0 or 1.
+func compare(a, b any) (int, error) {
+	switch av := a.(type) {
+	case string:
+		switch bv := b.(type) {
+		case string:
+			return strings.Compare(av, bv), nil
+		case time.Time:
+			return compareTimes(av, bv)
+		}
+	case time.Time:
+		return compareTimes(av, b)
+	case bool:
+		if bv, ok := b.(bool); ok {
+			return compareBools(av, bv), nil
+		}
+	}
+
+	if ar, ok := toRat(a); ok && !isString(a) {
+		if br, ok := toRat(b); ok && !isString(b) {
+			return ar.Cmp(br), nil
+		}
+	}
+
+	return 0, fmt.Errorf("cannot compare %v (%T) with %v (%T)", a, a, b, b)
+}
+
+func compareTimes(a, b any) (int, error) {
+	at, err := toTime(a)
+	if err != nil {
+		return 0, err
+	}
+
+	bt, err := toTime(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return at.Compare(bt), nil
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func isString(value any) bool {
+	_, ok := value.(string)
+
+	return ok
+}
@@ -0,0 +1,540 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// valueConverter converts a value of a query result to the Go type of the column type.
+type valueConverter func(t columnType, value any) (any, error)
+
+// typeConverter converts the values of a column type.
+type typeConverter struct {
+	convert valueConverter
+	// goType is the type of the converted values.
+	goType reflect.Type
+}
+
+var (
+	booleanConverter = typeConverter{convert: convertBoolean, goType: reflect.TypeFor[bool]()}
+	intConverter     = typeConverter{convert: convertInt, goType: reflect.TypeFor[int64]()}
+	uintConverter    = typeConverter{convert: convertUint, goType: reflect.TypeFor[uint64]()}
+	bigIntConverter  = typeConverter{convert: convertBigInt, goType: reflect.TypeFor[*big.Int]()}
+	floatConverter   = typeConverter{convert: convertFloat, goType: reflect.TypeFor[float64]()}
+	decimalConverter = typeConverter{convert: convertDecimal, goType: reflect.TypeFor[json.Number]()}
+	stringConverter  = typeConverter{convert: convertString, goType: reflect.TypeFor[string]()}
+	timeConverter    = typeConverter{convert: convertTime, goType: reflect.TypeFor[time.Time]()}
+)
+
+// converters holds the converters of the column types by their lower case names.
+// The values of the types missing here are returned as they're decoded from JSON.
+var converters = map[string]typeConverter{
+	// UInt8 is a Firebolt's representation of a boolean type.
+	"uint8":   booleanConverter,
+	"boolean": booleanConverter,
+	"bool":    booleanConverter,
+
+	"int8":         intConverter,
+	"int16":        intConverter,
+	"int32":        intConverter,
+	"int64":        intConverter,
+	"uint16":       intConverter,
+	"uint32":       intConverter,
+	"int":          intConverter,
+	"integer":      intConverter,
+	"bigint":       intConverter,
+	"long":         intConverter,
+	"uint64":       uintConverter,
+	"int128":       bigIntConverter,
+	"uint128":      bigIntConverter,
+	"int256":       bigIntConverter,
+	"uint256":      bigIntConverter,
+	"float32":      floatConverter,
+	"float64":      floatConverter,
+	"real":         floatConverter,
+	"float":        floatConverter,
+	"double":       floatConverter,
+	"decimal":      decimalConverter,
+	"numeric":      decimalConverter,
+	"string":       stringConverter,
+	"text":         stringConverter,
+	"date":         timeConverter,
+	"pgdate":       timeConverter,
+	"date32":       timeConverter,
+	"datetime":     timeConverter,
+	"datetime64":   timeConverter,
+	"timestamp":    timeConverter,
+	"timestampntz": timeConverter,
+	"timestamptz":  timeConverter,
+}
+
+// Column is a column of a query result.
+type Column struct {
+	Name string `json:"name"`
+	// Type is the Firebolt type of the column without the nullability, e.g. Int64 or Array(Nullable(String)).
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+// columnsFromMeta returns the columns of the query result metadata.
+func columnsFromMeta(meta []RunQueryResponseMeta) []Column {
+	columns := make([]Column, len(meta))
+
+	for i := range meta {
+		columns[i].Name = meta[i].Name
+		columns[i].Type, columns[i].Nullable = splitNullable(meta[i].Type)
+	}
+
+	return columns
+}
+
+// splitNullable returns the type without the nullability, e.g. Int32 for Nullable(Int32) or int for int null,
+// and reports whether the type is nullable.
+func splitNullable(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	lower := strings.ToLower(name)
+
+	switch {
+	case strings.HasPrefix(lower, "nullable(") && strings.HasSuffix(lower, ")"):
+		return name[len("nullable(") : len(name)-1], true
+	case strings.HasSuffix(lower, " not null"):
+		return strings.TrimSpace(name[:len(name)-len(" not null")]), false
+	case strings.HasSuffix(lower, " null"):
+		return strings.TrimSpace(name[:len(name)-len(" null")]), true
+	default:
+		return name, false
+	}
+}
+
+// columnType is a type of a column of a query result, e.g. Nullable(Array(Decimal(38, 2))).
+type columnType struct {
+	// name is the lower case name of the type without parameters, e.g. decimal.
+	name     string
+	nullable bool
+	// elem is the type of the elements of an array type.
+	elem *columnType
+	// precision is the number of digits of a decimal type, it's zero if the type doesn't have the parameter.
+	precision int
+	// scale is the number of digits after the decimal point of a decimal type, it's unknownScale if the type
+	// doesn't have the scale parameter.
+	scale int
+}
+
+// defaultDecimalScale is the scale of Firebolt's decimal types declared without the precision and the scale.
+const defaultDecimalScale = 9
+
+// maxDecimalPrecision is the precision of Firebolt's decimal types declared without the precision.
+const maxDecimalPrecision = 38
+
+// unknownScale is the scale of the decimal types without the scale parameter.
+const unknownScale = -1
+
+// parseColumnType parses the type of a column of query result metadata.
+// Both the Nullable(T) form and the new style "T null" form of nullable types are supported.
+func parseColumnType(name string) columnType {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if inner, ok := unwrapType(name, "nullable"); ok {
+		t := parseColumnType(inner)
+		t.nullable = true
+
+		return t
+	}
+
+	if strings.HasSuffix(name, " not null") {
+		return parseColumnType(strings.TrimSuffix(name, " not null"))
+	}
+
+	if strings.HasSuffix(name, " null") {
+		t := parseColumnType(strings.TrimSuffix(name, " null"))
+		t.nullable = true
+
+		return t
+	}
+
+	if inner, ok := unwrapType(name, "array"); ok {
+		elem := parseColumnType(inner)
+
+		return columnType{name: "array", elem: &elem}
+	}
+
+	t := columnType{name: name}
+
+	var params []string
+
+	// the parameters, e.g. the precision and the scale of Decimal(38, 2), are dropped from the name.
+	if i := strings.IndexByte(name, '('); i > 0 && strings.HasSuffix(name, ")") {
+		t.name = strings.TrimSpace(name[:i])
+		params = strings.Split(name[i+1:len(name)-1], ",")
+	}
+
+	if t.name == "decimal" || t.name == "numeric" {
+		t.scale = unknownScale
+
+		if len(params) > 0 {
+			t.precision, _ = strconv.Atoi(strings.TrimSpace(params[0]))
+		}
+
+		if len(params) == 2 {
+			if scale, err := strconv.Atoi(strings.TrimSpace(params[1])); err == nil {
+				t.scale = scale
+			}
+		}
+	}
+
+	// the multi-word names of the new style types.
+	switch t.name {
+	case "double precision":
+		t.name = "double"
+	case "timestamp with time zone":
+		t.name = "timestamptz"
+	case "timestamp without time zone":
+		t.name = "timestampntz"
+	}
+
+	return t
+}
+
+// unwrapType returns the parameter of the type, e.g. Int32 for Nullable(Int32), if the type has the name.
+func unwrapType(name, wrapper string) (string, bool) {
+	if !strings.HasPrefix(name, wrapper+"(") || !strings.HasSuffix(name, ")") {
+		return "", false
+	}
+
+	return name[len(wrapper)+1 : len(name)-1], true
+}
+
+// convert converts a value of a query result to the Go type of the column type.
+func (t columnType) convert(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	if t.elem != nil {
+		return t.convertArray(value)
+	}
+
+	converter, ok := converters[t.name]
+	if !ok {
+		return value, nil
+	}
+
+	return converter.convert(t, value)
+}
+
+// convertArray converts the value to a slice of the Go type of the elements, e.g. []int64 for Array(Int64),
+// or to []any if the elements are nullable or their type is unknown.
+func (t columnType) convertArray(value any) (any, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v (%T) is not an array", ErrCannotConvertValue, value, value)
+	}
+
+	elemType := t.elem.goType()
+	result := reflect.MakeSlice(reflect.SliceOf(elemType), len(items), len(items))
+
+	for i := range items {
+		item, err := t.elem.convert(items[i])
+		if err != nil {
+			return nil, fmt.Errorf("array element %d: %w", i, err)
+		}
+
+		if item == nil {
+			if elemType.Kind() != reflect.Interface {
+				return nil, fmt.Errorf("%w: NULL element %d of not nullable array", ErrCannotConvertValue, i)
+			}
+
+			continue
+		}
+
+		result.Index(i).Set(reflect.ValueOf(item))
+	}
+
+	return result.Interface(), nil
+}
+
+// goType returns the Go type of the converted values of the column type,
+// it's an empty interface type for nullable and unknown types.
+func (t columnType) goType() reflect.Type {
+	anyType := reflect.TypeFor[any]()
+
+	if t.nullable {
+		return anyType
+	}
+
+	if t.elem != nil {
+		return reflect.SliceOf(t.elem.goType())
+	}
+
+	if converter, ok := converters[t.name]; ok {
+		return converter.goType
+	}
+
+	return anyType
+}
+
+// definitionTypes holds the types of the column definitions by the lower case names of the column types,
+// the decimal and the array types are defined by their parameters.
+var definitionTypes = map[string]string{
+	"uint8":        "BOOLEAN",
+	"boolean":      "BOOLEAN",
+	"bool":         "BOOLEAN",
+	"int8":         "INT",
+	"int16":        "INT",
+	"int32":        "INT",
+	"uint16":       "INT",
+	"int":          "INT",
+	"integer":      "INT",
+	"uint32":       "BIGINT",
+	"int64":        "BIGINT",
+	"bigint":       "BIGINT",
+	"long":         "BIGINT",
+	"uint64":       "NUMERIC(38, 0)",
+	"int128":       "NUMERIC(38, 0)",
+	"uint128":      "NUMERIC(38, 0)",
+	"float32":      "REAL",
+	"real":         "REAL",
+	"float":        "REAL",
+	"float64":      "DOUBLE",
+	"double":       "DOUBLE",
+	"string":       "TEXT",
+	"text":         "TEXT",
+	"date":         "DATE",
+	"pgdate":       "DATE",
+	"date32":       "DATE",
+	"datetime":     "TIMESTAMP",
+	"datetime64":   "TIMESTAMP",
+	"timestamp":    "TIMESTAMP",
+	"timestampntz": "TIMESTAMP",
+	"timestamptz":  "TIMESTAMPTZ",
+}
+
+// ColumnDefinitionType returns the type of the definition of a column to create for the type of a query result
+// column, e.g. BIGINT for Int64 or ARRAY(TEXT NULL) for Array(Nullable(String)). The nullability of the column
+// isn't included. It returns false if the type can't be defined, e.g. it's unknown or out of the decimal range.
+func ColumnDefinitionType(name string) (string, bool) {
+	return parseColumnType(name).definitionType()
+}
+
+// definitionType returns the type of the column definition of the column type.
+func (t columnType) definitionType() (string, bool) {
+	switch {
+	case t.elem != nil:
+		elem, ok := t.elem.definitionType()
+		if !ok {
+			return "", false
+		}
+
+		if t.elem.nullable {
+			elem += " NULL"
+		}
+
+		return "ARRAY(" + elem + ")", true
+	case t.name == "decimal" || t.name == "numeric":
+		precision, scale := t.precision, t.scale
+		if precision == 0 {
+			precision = maxDecimalPrecision
+		}
+
+		if scale == unknownScale {
+			scale = defaultDecimalScale
+		}
+
+		if precision > maxDecimalPrecision || scale > precision {
+			return "", false
+		}
+
+		return fmt.Sprintf("NUMERIC(%d, %d)", precision, scale), true
+	default:
+		definitionType, ok := definitionTypes[t.name]
+
+		return definitionType, ok
+	}
+}
+
+func convertBoolean(_ columnType, value any) (any, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case float64:
+		return v != 0, nil
+	case json.Number:
+		return v.String() != "0", nil
+	case string:
+		switch strings.ToLower(v) {
+		case "1", "t", "true":
+			return true, nil
+		case "0", "f", "false":
+			return false, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %v (%T) to boolean", ErrCannotConvertValue, value, value)
+}
+
+func convertInt(_ columnType, value any) (any, error) {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), nil
+		}
+	case json.Number:
+		if n, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return n, nil
+		}
+	case string:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %v (%T) to integer", ErrCannotConvertValue, value, value)
+}
+
+func convertUint(_ columnType, value any) (any, error) {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) && v >= 0 && v < math.MaxUint64 {
+			return uint64(v), nil
+		}
+	case json.Number:
+		if n, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return n, nil
+		}
+	case string:
+		if n, err := strconv.ParseUint(v, 10, 64); err == nil {
+			return n, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %v (%T) to unsigned integer", ErrCannotConvertValue, value, value)
+}
+
+// convertBigInt converts the value of the integer types wider than 64 bits to *big.Int.
+func convertBigInt(_ columnType, value any) (any, error) {
+	var (
+		n  *big.Int
+		ok bool
+	)
+
+	switch v := value.(type) {
+	case json.Number:
+		n, ok = new(big.Int).SetString(v.String(), 10)
+	case string:
+		n, ok = new(big.Int).SetString(v, 10)
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			n, _ = new(big.Float).SetFloat64(v).Int(nil)
+			ok = true
+		}
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %v (%T) to big integer", ErrCannotConvertValue, value, value)
+	}
+
+	return n, nil
+}
+
+// convertFloat converts the value to float64, including the NaN and infinite values returned as strings.
+func convertFloat(_ columnType, value any) (any, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f, nil
+		}
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %v (%T) to float", ErrCannotConvertValue, value, value)
+}
+
+// convertDecimal converts the value to json.Number with the scale of the type, so no precision is lost,
+// and the value is written as a JSON number. If the scale of the type is unknown, the value keeps its own precision.
+func convertDecimal(t columnType, value any) (any, error) {
+	var (
+		r  *big.Rat
+		ok bool
+	)
+
+	switch v := value.(type) {
+	case float64:
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			r, ok = new(big.Rat).SetFloat64(v), true
+		}
+	case json.Number:
+		r, ok = new(big.Rat).SetString(v.String())
+	case string:
+		r, ok = new(big.Rat).SetString(v)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %v (%T) to decimal", ErrCannotConvertValue, value, value)
+	}
+
+	if t.scale != unknownScale {
+		return json.Number(r.FloatString(t.scale)), nil
+	}
+
+	if f, ok := value.(float64); ok {
+		return json.Number(strconv.FormatFloat(f, 'f', -1, 64)), nil
+	}
+
+	// the values which can't be written with a finite number of digits, e.g. 1/3, are written with the default
+	// scale of Firebolt.
+	scale, exact := r.FloatPrec()
+	if !exact {
+		scale = defaultDecimalScale
+	}
+
+	return json.Number(r.FloatString(scale)), nil
+}
+
+func convertString(_ columnType, value any) (any, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%v (%T): %w", value, value, ErrCannotCastValueToString)
+	}
+
+	return s, nil
+}
+
+// convertTime converts the value to time.Time, the timestamps with a time zone are converted to UTC.
+func convertTime(_ columnType, value any) (any, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%v (%T): %w", value, value, ErrCannotCastValueToString)
+	}
+
+	t, err := parseTime(s)
+	if err != nil {
+		return nil, fmt.Errorf("convert %q to time: %w", s, err)
+	}
+
+	return t.UTC(), nil
+}
@@ -0,0 +1,415 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseColumnType(t *testing.T) {
+	tests := []struct {
+		name     string
+		typeName string
+		want     columnType
+	}{
+		{
+			name:     "simple",
+			typeName: "Int32",
+			want:     columnType{name: "int32"},
+		},
+		{
+			name:     "nullable",
+			typeName: "Nullable(DateTime64(3))",
+			want:     columnType{name: "datetime64", nullable: true},
+		},
+		{
+			name:     "new style nullable",
+			typeName: "timestamptz null",
+			want:     columnType{name: "timestamptz", nullable: true},
+		},
+		{
+			name:     "new style not nullable",
+			typeName: "bigint not null",
+			want:     columnType{name: "bigint"},
+		},
+		{
+			name:     "decimal",
+			typeName: "Decimal(38, 9)",
+			want:     columnType{name: "decimal", precision: 38, scale: 9},
+		},
+		{
+			name:     "nested array",
+			typeName: "Array(Array(Nullable(Int64)))",
+			want: columnType{name: "array", elem: &columnType{
+				name: "array", elem: &columnType{name: "int64", nullable: true},
+			}},
+		},
+		{
+			name:     "multi-word name",
+			typeName: "double precision",
+			want:     columnType{name: "double"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseColumnType(tt.typeName); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parse = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnDefinitionType(t *testing.T) {
+	tests := []struct {
+		name     string
+		typeName string
+		want     string
+		wantOK   bool
+	}{
+		{name: "integer", typeName: "Int32", want: "INT", wantOK: true},
+		{name: "new style integer", typeName: "bigint", want: "BIGINT", wantOK: true},
+		{name: "unsigned integer", typeName: "UInt64", want: "NUMERIC(38, 0)", wantOK: true},
+		{name: "boolean", typeName: "UInt8", want: "BOOLEAN", wantOK: true},
+		{name: "string", typeName: "String", want: "TEXT", wantOK: true},
+		{name: "timestamp", typeName: "DateTime64(6)", want: "TIMESTAMP", wantOK: true},
+		{name: "timestamp with time zone", typeName: "timestamp with time zone", want: "TIMESTAMPTZ", wantOK: true},
+		{name: "decimal", typeName: "Decimal(18, 2)", want: "NUMERIC(18, 2)", wantOK: true},
+		{name: "decimal without parameters", typeName: "numeric", want: "NUMERIC(38, 9)", wantOK: true},
+		{name: "array", typeName: "Array(Nullable(String))", want: "ARRAY(TEXT NULL)", wantOK: true},
+		{name: "nested array", typeName: "array(array(int) null)", want: "ARRAY(ARRAY(INT) NULL)", wantOK: true},
+		{name: "unknown type", typeName: "Geography"},
+		{name: "array of unknown type", typeName: "Array(Geography)"},
+		{name: "decimal out of range", typeName: "Decimal(76, 0)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ColumnDefinitionType(tt.typeName)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("column definition type = %q, %t, want %q, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestColumnsFromMeta(t *testing.T) {
+	meta := []RunQueryResponseMeta{
+		{Name: "id", Type: "Int32"},
+		{Name: "name", Type: "Nullable(String)"},
+		{Name: "tags", Type: "Array(Nullable(String))"},
+		{Name: "created_at", Type: "timestamptz null"},
+		{Name: "amount", Type: "numeric(38, 2) not null"},
+	}
+
+	want := []Column{
+		{Name: "id", Type: "Int32"},
+		{Name: "name", Type: "String", Nullable: true},
+		{Name: "tags", Type: "Array(Nullable(String))"},
+		{Name: "created_at", Type: "timestamptz", Nullable: true},
+		{Name: "amount", Type: "numeric(38, 2)"},
+	}
+
+	if got := columnsFromMeta(meta); !reflect.DeepEqual(got, want) {
+		t.Errorf("columns = %+v, want %+v", got, want)
+	}
+}
+
+func TestPrepareRunQueryResponseData(t *testing.T) {
+	tests := []struct {
+		name     string
+		metaType string
+		value    any
+		want     any
+		wantErr  bool
+	}{
+		{
+			name:     "UInt8 to bool",
+			metaType: "UInt8",
+			value:    float64(1),
+			want:     true,
+		},
+		{
+			name:     "Boolean",
+			metaType: "Boolean",
+			value:    false,
+			want:     false,
+		},
+		{
+			name:     "Boolean from string",
+			metaType: "boolean",
+			value:    "t",
+			want:     true,
+		},
+		{
+			name:     "Boolean invalid",
+			metaType: "UInt8",
+			value:    "yes",
+			wantErr:  true,
+		},
+		{
+			name:     "Int32",
+			metaType: "Int32",
+			value:    float64(42),
+			want:     int64(42),
+		},
+		{
+			name:     "Int64 from number",
+			metaType: "Int64",
+			value:    json.Number("9007199254740993"),
+			want:     int64(9007199254740993),
+		},
+		{
+			name:     "Int64 from string",
+			metaType: "Int64",
+			value:    "-9223372036854775808",
+			want:     int64(-9223372036854775808),
+		},
+		{
+			name:     "Int64 fractional",
+			metaType: "Int64",
+			value:    1.5,
+			wantErr:  true,
+		},
+		{
+			name:     "UInt64",
+			metaType: "UInt64",
+			value:    json.Number("18446744073709551615"),
+			want:     uint64(18446744073709551615),
+		},
+		{
+			name:     "UInt64 negative",
+			metaType: "UInt64",
+			value:    json.Number("-1"),
+			wantErr:  true,
+		},
+		{
+			name:     "Int128",
+			metaType: "Int128",
+			value:    json.Number("-170141183460469231731687303715884105728"),
+			want:     mustBigInt("-170141183460469231731687303715884105728"),
+		},
+		{
+			name:     "UInt256 invalid",
+			metaType: "UInt256",
+			value:    json.Number("1.5"),
+			wantErr:  true,
+		},
+		{
+			name:     "Decimal",
+			metaType: "Decimal(38, 2)",
+			value:    json.Number("12345678901234567890.5"),
+			want:     json.Number("12345678901234567890.50"),
+		},
+		{
+			name:     "Decimal from string",
+			metaType: "numeric(10, 3)",
+			value:    "1.25",
+			want:     json.Number("1.250"),
+		},
+		{
+			name:     "Decimal without scale",
+			metaType: "numeric",
+			value:    json.Number("12345678901234567890.125"),
+			want:     json.Number("12345678901234567890.125"),
+		},
+		{
+			name:     "Decimal without scale from float",
+			metaType: "Decimal(38)",
+			value:    1.5,
+			want:     json.Number("1.5"),
+		},
+		{
+			name:     "Decimal invalid",
+			metaType: "Decimal(10, 2)",
+			value:    "abc",
+			wantErr:  true,
+		},
+		{
+			name:     "Float32",
+			metaType: "Float32",
+			value:    json.Number("1.5"),
+			want:     1.5,
+		},
+		{
+			name:     "Float64 infinity",
+			metaType: "Float64",
+			value:    "inf",
+			want:     math.Inf(1),
+		},
+		{
+			name:     "String",
+			metaType: "String",
+			value:    "text",
+			want:     "text",
+		},
+		{
+			name:     "String invalid",
+			metaType: "String",
+			value:    float64(1),
+			wantErr:  true,
+		},
+		{
+			name:     "Date",
+			metaType: "Date",
+			value:    "2022-01-02",
+			want:     time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "pgdate",
+			metaType: "pgdate",
+			value:    "2022-01-02",
+			want:     time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "DateTime",
+			metaType: "DateTime",
+			value:    "2022-01-02 03:04:05",
+			want:     time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			name:     "DateTime64",
+			metaType: "DateTime64(6)",
+			value:    "2022-01-02 03:04:05.123456",
+			want:     time.Date(2022, 1, 2, 3, 4, 5, 123456000, time.UTC),
+		},
+		{
+			name:     "timestampntz",
+			metaType: "timestampntz",
+			value:    "2022-01-02 03:04:05.1",
+			want:     time.Date(2022, 1, 2, 3, 4, 5, 100000000, time.UTC),
+		},
+		{
+			name:     "timestamptz",
+			metaType: "timestamptz",
+			value:    "2022-01-02 03:04:05+02",
+			want:     time.Date(2022, 1, 2, 1, 4, 5, 0, time.UTC),
+		},
+		{
+			name:     "timestamp invalid",
+			metaType: "timestamp",
+			value:    "yesterday",
+			wantErr:  true,
+		},
+		{
+			name:     "timestamp from number",
+			metaType: "timestamp",
+			value:    json.Number("1656064800"),
+			wantErr:  true,
+		},
+		{
+			name:     "Nullable with NULL",
+			metaType: "Nullable(Int64)",
+			value:    nil,
+			want:     nil,
+		},
+		{
+			name:     "Nullable with value",
+			metaType: "Nullable(Decimal(10, 1))",
+			value:    float64(2),
+			want:     json.Number("2.0"),
+		},
+		{
+			name:     "Array",
+			metaType: "Array(Int64)",
+			value:    []any{json.Number("1"), json.Number("2")},
+			want:     []int64{1, 2},
+		},
+		{
+			name:     "Array of nullable elements",
+			metaType: "Array(Nullable(Int32))",
+			value:    []any{json.Number("1"), nil},
+			want:     []any{int64(1), nil},
+		},
+		{
+			name:     "nested Array",
+			metaType: "Array(Array(String))",
+			value:    []any{[]any{"a", "b"}, []any{}},
+			want:     [][]string{{"a", "b"}, {}},
+		},
+		{
+			name:     "nested Array with NULL",
+			metaType: "Array(Array(Nullable(Date)))",
+			value:    []any{[]any{"2022-01-02", nil}, []any{}},
+			want:     [][]any{{time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), nil}, {}},
+		},
+		{
+			name:     "nullable Array",
+			metaType: "Nullable(Array(Int32))",
+			value:    nil,
+			want:     nil,
+		},
+		{
+			name:     "Array of unknown type",
+			metaType: "Array(Geography)",
+			value:    []any{"POINT(1 2)"},
+			want:     []any{"POINT(1 2)"},
+		},
+		{
+			name:     "NULL in not nullable Array",
+			metaType: "Array(Int32)",
+			value:    []any{nil},
+			wantErr:  true,
+		},
+		{
+			name:     "Array invalid",
+			metaType: "Array(Int64)",
+			value:    "[1, 2]",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown type",
+			metaType: "Geography",
+			value:    "POINT(1 2)",
+			want:     "POINT(1 2)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &RunQueryResponse{
+				Meta: []RunQueryResponseMeta{{Name: "col", Type: tt.metaType}},
+				Data: []map[string]any{{"col": tt.value}},
+			}
+
+			err := prepareRunQueryResponseData(resp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("prepare error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got := resp.Data[0]["col"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("value = %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func mustBigInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid big integer " + s)
+	}
+
+	return n
+}
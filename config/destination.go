@@ -16,11 +16,140 @@ package config
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/config/validator"
+)
+
+const (
+	// KeyKeyColumns is a config name for a list of key columns.
+	KeyKeyColumns = "keyColumns"
+	// KeyMaxInsertRows is a config name for the maximum number of rows in a single INSERT statement.
+	KeyMaxInsertRows = "maxInsertRows"
+	// KeyMaxInsertBytes is a config name for the maximum size of a single INSERT statement.
+	KeyMaxInsertBytes = "maxInsertBytes"
+	// KeyWriteMode is a config name for a write mode.
+	KeyWriteMode = "writeMode"
+	// KeyUpsertMethod is a config name for a method of upserting records.
+	KeyUpsertMethod = "upsertMethod"
+	// KeySchemaEvolution is a config name for a way of handling the payload fields missing in the table.
+	KeySchemaEvolution = "schemaEvolution"
+	// KeyColumnTypesTTL is a config name for a time after which the column types of a table are loaded again.
+	KeyColumnTypesTTL = "columnTypesTTL"
+	// KeyAutoCreateTable is a config name for a flag of creating the table if it doesn't exist.
+	KeyAutoCreateTable = "autoCreateTable"
+	// KeyTableType is a config name for a type of the created table.
+	KeyTableType = "tableType"
+	// KeyPrimaryIndex is a config name for a list of primary index columns of the created table.
+	KeyPrimaryIndex = "primaryIndex"
+	// KeyTableTemplate is a config name for a template of the table names the records are written to.
+	KeyTableTemplate = "tableTemplate"
+	// KeyColumnMapping is a config name for a list of the payload fields and the columns they're written to.
+	KeyColumnMapping = "columnMapping"
+	// KeyIncludeFields is a config name for a list of the payload fields written to the table.
+	KeyIncludeFields = "includeFields"
+	// KeyExcludeFields is a config name for a list of the payload fields which aren't written to the table.
+	KeyExcludeFields = "excludeFields"
+
+	// WriteModeInsert is a write mode in which records are inserted.
+	WriteModeInsert = "insert"
+	// WriteModeUpsert is a write mode in which records replace the existing rows with the same keys.
+	WriteModeUpsert = "upsert"
+
+	// UpsertMethodDeleteInsert is an upsert method that deletes the existing rows and inserts the new ones.
+	UpsertMethodDeleteInsert = "deleteInsert"
+	// UpsertMethodMerge is an upsert method that uses MERGE statements.
+	UpsertMethodMerge = "merge"
+
+	// SchemaEvolutionNone is a schema evolution mode in which records with unknown fields fail to be written.
+	SchemaEvolutionNone = "none"
+	// SchemaEvolutionAddColumns is a schema evolution mode in which the columns of unknown fields are added.
+	SchemaEvolutionAddColumns = "addColumns"
+	// SchemaEvolutionIgnore is a schema evolution mode in which unknown fields are dropped.
+	SchemaEvolutionIgnore = "ignore"
+
+	// TableTypeFact is a type of fact tables.
+	TableTypeFact = "fact"
+	// TableTypeDimension is a type of dimension tables.
+	TableTypeDimension = "dimension"
+
+	// defaultMaxInsertRows is a default maximum number of rows in a single INSERT statement.
+	defaultMaxInsertRows = 1000
+	// defaultMaxInsertBytes is a default maximum size of a single INSERT statement in bytes.
+	defaultMaxInsertBytes = 1 << 20
+	// defaultColumnTypesTTL is a default time after which the column types of a table are loaded again.
+	defaultColumnTypesTTL = 5 * time.Minute
 )
 
 // Destination holds destination-related configurable values.
 type Destination struct {
 	General
+
+	// KeyColumns - columns which values identify the rows to update or delete.
+	// If it's empty all the fields of the record key are used.
+	KeyColumns []string
+	// MaxInsertRows - the maximum number of rows in a single INSERT statement.
+	MaxInsertRows int `validate:"gte=1"`
+	// MaxInsertBytes - the maximum size of a single INSERT statement in bytes.
+	// A statement always contains at least one row, even if the row is larger.
+	MaxInsertBytes int `validate:"gte=1"`
+	// WriteMode - the way records with the create and snapshot operations are written.
+	WriteMode string
+	// UpsertMethod - the way records are upserted if the WriteMode is WriteModeUpsert.
+	UpsertMethod string
+	// SchemaEvolution - the way the payload fields missing in the table are handled.
+	SchemaEvolution string
+	// ColumnTypesTTL - the time after which the column types of a table are loaded again,
+	// zero means they're loaded again only after a schema error.
+	ColumnTypesTTL time.Duration
+	// AutoCreateTable - create the table from the first record if it doesn't exist.
+	AutoCreateTable bool
+	// TableType - the type of the created table, TableTypeFact or TableTypeDimension.
+	TableType string
+	// PrimaryIndex - the primary index columns of the created table.
+	// If it's empty the columns of the record key are used.
+	PrimaryIndex []string
+	// TableDestinations - the settings of the tables by their names, they override the top-level ones.
+	TableDestinations map[string]TableDestination
+	// TableTemplate - a Go template rendering the name of the table a record is written to from the record,
+	// the record is written to the Table if the rendered name is empty.
+	TableTemplate string
+	// ColumnMapping - the way the payload fields are written to the columns.
+	ColumnMapping ColumnMapping
+}
+
+// ColumnMapping holds the way the payload fields are written to the columns.
+// The fields are referred by paths, the names of the nested fields are separated by dots, e.g. address.city.
+type ColumnMapping struct {
+	// Columns - the names of the columns by the paths of the fields written to them.
+	// The nested fields are written to the columns named by their paths with underscores by default.
+	Columns map[string]string
+	// IncludeFields - the paths of the fields written to the table, all the top-level fields are written if it's empty.
+	IncludeFields []string
+	// ExcludeFields - the paths of the fields which aren't written to the table.
+	ExcludeFields []string
+}
+
+// TableDestination holds the settings of writing the records to a table.
+type TableDestination struct {
+	// KeyColumns - columns which values identify the rows to update or delete.
+	KeyColumns []string
+	// WriteMode - the way records with the create and snapshot operations are written.
+	WriteMode string
+	// UpsertMethod - the way records are upserted if the WriteMode is WriteModeUpsert.
+	UpsertMethod string
+	// AutoCreateTable - create the table from the first record if it doesn't exist.
+	AutoCreateTable bool
+	// TableType - the type of the created table, TableTypeFact or TableTypeDimension.
+	TableType string
+	// PrimaryIndex - the primary index columns of the created table.
+	PrimaryIndex []string
+	// ColumnMapping - the way the payload fields are written to the columns.
+	ColumnMapping ColumnMapping
 }
 
 // ParseDestination attempts to parse plugins.Config into a Destination struct.
@@ -30,7 +159,305 @@ func ParseDestination(cfg map[string]string) (Destination, error) {
 		return Destination{}, fmt.Errorf("parse general config: %w", err)
 	}
 
-	destination := Destination{general}
+	destination := Destination{
+		General:         general,
+		MaxInsertRows:   defaultMaxInsertRows,
+		MaxInsertBytes:  defaultMaxInsertBytes,
+		WriteMode:       WriteModeInsert,
+		UpsertMethod:    UpsertMethodDeleteInsert,
+		TableType:       TableTypeFact,
+		SchemaEvolution: SchemaEvolutionNone,
+		ColumnTypesTTL:  defaultColumnTypesTTL,
+	}
+
+	if colsRaw := cfg[KeyKeyColumns]; colsRaw != "" {
+		destination.KeyColumns = strings.Split(colsRaw, ",")
+	}
+
+	if destination.MaxInsertRows, err = parseInt(cfg, KeyMaxInsertRows, destination.MaxInsertRows); err != nil {
+		return Destination{}, err
+	}
+
+	if destination.MaxInsertBytes, err = parseInt(cfg, KeyMaxInsertBytes, destination.MaxInsertBytes); err != nil {
+		return Destination{}, err
+	}
+
+	if cfg[KeyWriteMode] != "" {
+		destination.WriteMode = cfg[KeyWriteMode]
+	}
+
+	if cfg[KeyUpsertMethod] != "" {
+		destination.UpsertMethod = cfg[KeyUpsertMethod]
+	}
+
+	if cfg[KeySchemaEvolution] != "" {
+		destination.SchemaEvolution = cfg[KeySchemaEvolution]
+	}
+
+	if destination.ColumnTypesTTL, err = parseDuration(cfg, KeyColumnTypesTTL, destination.ColumnTypesTTL); err != nil {
+		return Destination{}, err
+	}
+
+	if destination.AutoCreateTable, err = parseBool(cfg, KeyAutoCreateTable, false); err != nil {
+		return Destination{}, err
+	}
+
+	if cfg[KeyTableType] != "" {
+		destination.TableType = strings.ToLower(cfg[KeyTableType])
+	}
+
+	if indexRaw := cfg[KeyPrimaryIndex]; indexRaw != "" {
+		destination.PrimaryIndex = strings.Split(indexRaw, ",")
+	}
+
+	if destination.TableTemplate, err = parseTemplate(cfg, KeyTableTemplate); err != nil {
+		return Destination{}, err
+	}
+
+	if destination.ColumnMapping, err = parseColumnMapping(cfg, ColumnMapping{}); err != nil {
+		return Destination{}, err
+	}
+
+	if err = destination.validate(); err != nil {
+		return Destination{}, err
+	}
+
+	if destination.TableDestinations, err = destination.parseTableDestinations(cfg); err != nil {
+		return Destination{}, err
+	}
 
 	return destination, nil
 }
+
+// validate checks the parsed config values.
+func (d Destination) validate() error {
+	if err := validator.Validate(d); err != nil {
+		return err
+	}
+
+	if err := requireValues(configValue{KeyTable, d.Table}); err != nil {
+		return err
+	}
+
+	if err := validateOneOf(KeyWriteMode, d.WriteMode, WriteModeInsert, WriteModeUpsert); err != nil {
+		return err
+	}
+
+	if err := validateOneOf(KeyUpsertMethod, d.UpsertMethod, UpsertMethodDeleteInsert, UpsertMethodMerge); err != nil {
+		return err
+	}
+
+	err := validateOneOf(KeySchemaEvolution, d.SchemaEvolution,
+		SchemaEvolutionNone, SchemaEvolutionAddColumns, SchemaEvolutionIgnore)
+	if err != nil {
+		return err
+	}
+
+	return validateOneOf(KeyTableType, d.TableType, TableTypeFact, TableTypeDimension)
+}
+
+// defaultTableDestination returns the top-level settings of the tables.
+func (d Destination) defaultTableDestination() TableDestination {
+	return TableDestination{
+		KeyColumns:      d.KeyColumns,
+		WriteMode:       d.WriteMode,
+		UpsertMethod:    d.UpsertMethod,
+		AutoCreateTable: d.AutoCreateTable,
+		TableType:       d.TableType,
+		PrimaryIndex:    d.PrimaryIndex,
+		ColumnMapping:   d.ColumnMapping,
+	}
+}
+
+// parseTableDestinations parses the settings of the tables, the settings which are not set for a table
+// are the top-level ones.
+func (d Destination) parseTableDestinations(cfg map[string]string) (map[string]TableDestination, error) {
+	settings, err := parseTableSettings(cfg, KeyKeyColumns, KeyWriteMode, KeyUpsertMethod, KeyAutoCreateTable,
+		KeyTableType, KeyPrimaryIndex, KeyColumnMapping, KeyIncludeFields, KeyExcludeFields)
+	if err != nil {
+		return nil, err
+	}
+
+	var tableDestinations map[string]TableDestination
+
+	for table, values := range settings {
+		tableDestination, err := d.parseTableDestination(values)
+		if err != nil {
+			return nil, fmt.Errorf("table %q: %w", table, err)
+		}
+
+		if tableDestinations == nil {
+			tableDestinations = make(map[string]TableDestination)
+		}
+
+		tableDestinations[table] = tableDestination
+	}
+
+	return tableDestinations, nil
+}
+
+// parseTableDestination parses the settings of a table by the setting names.
+func (d Destination) parseTableDestination(values map[string]string) (TableDestination, error) {
+	var (
+		tableDestination = d.defaultTableDestination()
+		err              error
+	)
+
+	if values[KeyKeyColumns] != "" {
+		tableDestination.KeyColumns = strings.Split(values[KeyKeyColumns], ",")
+	}
+
+	if values[KeyWriteMode] != "" {
+		tableDestination.WriteMode = values[KeyWriteMode]
+	}
+
+	if values[KeyUpsertMethod] != "" {
+		tableDestination.UpsertMethod = values[KeyUpsertMethod]
+	}
+
+	if values[KeyTableType] != "" {
+		tableDestination.TableType = strings.ToLower(values[KeyTableType])
+	}
+
+	if values[KeyPrimaryIndex] != "" {
+		tableDestination.PrimaryIndex = strings.Split(values[KeyPrimaryIndex], ",")
+	}
+
+	tableDestination.AutoCreateTable, err = parseBool(values, KeyAutoCreateTable, tableDestination.AutoCreateTable)
+	if err != nil {
+		return TableDestination{}, err
+	}
+
+	if tableDestination.ColumnMapping, err = parseColumnMapping(values, tableDestination.ColumnMapping); err != nil {
+		return TableDestination{}, err
+	}
+
+	if err = validateOneOf(KeyWriteMode, tableDestination.WriteMode, WriteModeInsert, WriteModeUpsert); err != nil {
+		return TableDestination{}, err
+	}
+
+	err = validateOneOf(KeyUpsertMethod, tableDestination.UpsertMethod, UpsertMethodDeleteInsert, UpsertMethodMerge)
+	if err != nil {
+		return TableDestination{}, err
+	}
+
+	err = validateOneOf(KeyTableType, tableDestination.TableType, TableTypeFact, TableTypeDimension)
+	if err != nil {
+		return TableDestination{}, err
+	}
+
+	return tableDestination, nil
+}
+
+// validateOneOf returns an error if the config value of the key is not one of the allowed values.
+func validateOneOf(key, value string, allowed ...string) error {
+	if slices.Contains(allowed, value) {
+		return nil
+	}
+
+	return fmt.Errorf("%q config value must be one of %q", key, allowed)
+}
+
+// parseInt returns the config value of the key converted to int, or the defaultValue if the value is empty.
+func parseInt(cfg map[string]string, key string, defaultValue int) (int, error) {
+	if cfg[key] == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(cfg[key])
+	if err != nil {
+		return 0, fmt.Errorf("%q config value must be int", key)
+	}
+
+	return value, nil
+}
+
+// parseBool returns the config value of the key converted to bool, or the defaultValue if the value is empty.
+func parseBool(cfg map[string]string, key string, defaultValue bool) (bool, error) {
+	if cfg[key] == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseBool(cfg[key])
+	if err != nil {
+		return false, fmt.Errorf("%q config value must be bool", key)
+	}
+
+	return value, nil
+}
+
+// parseTemplate returns the config value of the key, checking that it's a valid Go template.
+func parseTemplate(cfg map[string]string, key string) (string, error) {
+	value := strings.TrimSpace(cfg[key])
+	if value == "" {
+		return "", nil
+	}
+
+	if _, err := template.New(key).Parse(value); err != nil {
+		return "", fmt.Errorf("%q config value must be a valid template: %w", key, err)
+	}
+
+	return value, nil
+}
+
+// parseColumnMapping returns the column mapping of the config values, the values which are not set are taken
+// from the defaultMapping. The mapping is a comma separated list of pairs of field paths and column names,
+// e.g. userName:user_name,address.city:city.
+func parseColumnMapping(cfg map[string]string, defaultMapping ColumnMapping) (ColumnMapping, error) {
+	mapping := defaultMapping
+
+	if cfg[KeyColumnMapping] != "" {
+		mapping.Columns = make(map[string]string)
+
+		for _, pair := range strings.Split(cfg[KeyColumnMapping], ",") {
+			field, column, ok := strings.Cut(pair, ":")
+
+			field, column = strings.TrimSpace(field), strings.TrimSpace(column)
+			if !ok || field == "" || column == "" {
+				return ColumnMapping{}, fmt.Errorf("%q config value must be a list of field:column pairs, got %q",
+					KeyColumnMapping, pair)
+			}
+
+			if _, ok = mapping.Columns[field]; ok {
+				return ColumnMapping{}, fmt.Errorf("%q config value: field %q is mapped twice", KeyColumnMapping, field)
+			}
+
+			mapping.Columns[field] = column
+		}
+	}
+
+	if cfg[KeyIncludeFields] != "" {
+		mapping.IncludeFields = splitFields(cfg[KeyIncludeFields])
+	}
+
+	if cfg[KeyExcludeFields] != "" {
+		mapping.ExcludeFields = splitFields(cfg[KeyExcludeFields])
+	}
+
+	return mapping, nil
+}
+
+// splitFields splits the comma separated list of field paths.
+func splitFields(value string) []string {
+	fields := strings.Split(value, ",")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	return fields
+}
+
+// parseDuration returns the config value of the key converted to a non-negative time.Duration,
+// or the defaultValue if the value is empty.
+func parseDuration(cfg map[string]string, key string, defaultValue time.Duration) (time.Duration, error) {
+	if cfg[key] == "" {
+		return defaultValue, nil
+	}
+
+	value, err := time.ParseDuration(cfg[key])
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%q config value must be a non-negative duration", key)
+	}
+
+	return value, nil
+}
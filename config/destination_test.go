@@ -0,0 +1,511 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDestination(t *testing.T) {
+	general := General{
+		Email:       "test@test.com",
+		Password:    "12345",
+		AccountName: "super_account",
+		EngineName:  "super_engine",
+		DB:          "db",
+		Table:       "test",
+	}
+
+	tests := []struct {
+		name    string
+		cfg     map[string]string
+		want    Destination
+		wantErr bool
+	}{
+		{
+			name: "valid config",
+			cfg: map[string]string{
+				KeyEmail:       "test@test.com",
+				KeyPassword:    "12345",
+				KeyAccountName: "super_account",
+				KeyEngineName:  "super_engine",
+				KeyDB:          "db",
+				KeyTable:       "test",
+			},
+			want: Destination{
+				General:         general,
+				MaxInsertRows:   defaultMaxInsertRows,
+				MaxInsertBytes:  defaultMaxInsertBytes,
+				WriteMode:       WriteModeInsert,
+				UpsertMethod:    UpsertMethodDeleteInsert,
+				TableType:       TableTypeFact,
+				SchemaEvolution: SchemaEvolutionNone,
+				ColumnTypesTTL:  defaultColumnTypesTTL,
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid config, custom insert limits",
+			cfg: map[string]string{
+				KeyEmail:          "test@test.com",
+				KeyPassword:       "12345",
+				KeyAccountName:    "super_account",
+				KeyEngineName:     "super_engine",
+				KeyDB:             "db",
+				KeyTable:          "test",
+				KeyMaxInsertRows:  "10",
+				KeyMaxInsertBytes: "4096",
+			},
+			want: Destination{
+				General:         general,
+				MaxInsertRows:   10,
+				MaxInsertBytes:  4096,
+				WriteMode:       WriteModeInsert,
+				UpsertMethod:    UpsertMethodDeleteInsert,
+				TableType:       TableTypeFact,
+				SchemaEvolution: SchemaEvolutionNone,
+				ColumnTypesTTL:  defaultColumnTypesTTL,
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid config, key columns",
+			cfg: map[string]string{
+				KeyEmail:       "test@test.com",
+				KeyPassword:    "12345",
+				KeyAccountName: "super_account",
+				KeyEngineName:  "super_engine",
+				KeyDB:          "db",
+				KeyTable:       "test",
+				KeyKeyColumns:  "id,name",
+			},
+			want: Destination{
+				General:         general,
+				KeyColumns:      []string{"id", "name"},
+				MaxInsertRows:   defaultMaxInsertRows,
+				MaxInsertBytes:  defaultMaxInsertBytes,
+				WriteMode:       WriteModeInsert,
+				UpsertMethod:    UpsertMethodDeleteInsert,
+				TableType:       TableTypeFact,
+				SchemaEvolution: SchemaEvolutionNone,
+				ColumnTypesTTL:  defaultColumnTypesTTL,
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid config, upsert",
+			cfg: map[string]string{
+				KeyEmail:        "test@test.com",
+				KeyPassword:     "12345",
+				KeyAccountName:  "super_account",
+				KeyEngineName:   "super_engine",
+				KeyDB:           "db",
+				KeyTable:        "test",
+				KeyWriteMode:    "upsert",
+				KeyUpsertMethod: "merge",
+			},
+			want: Destination{
+				General:         general,
+				MaxInsertRows:   defaultMaxInsertRows,
+				MaxInsertBytes:  defaultMaxInsertBytes,
+				WriteMode:       WriteModeUpsert,
+				UpsertMethod:    UpsertMethodMerge,
+				TableType:       TableTypeFact,
+				SchemaEvolution: SchemaEvolutionNone,
+				ColumnTypesTTL:  defaultColumnTypesTTL,
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid config, auto create table",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTable:           "test",
+				KeyAutoCreateTable: "true",
+				KeyTableType:       "DIMENSION",
+				KeyPrimaryIndex:    "id,name",
+			},
+			want: Destination{
+				General:         general,
+				MaxInsertRows:   defaultMaxInsertRows,
+				MaxInsertBytes:  defaultMaxInsertBytes,
+				WriteMode:       WriteModeInsert,
+				UpsertMethod:    UpsertMethodDeleteInsert,
+				AutoCreateTable: true,
+				TableType:       TableTypeDimension,
+				SchemaEvolution: SchemaEvolutionNone,
+				ColumnTypesTTL:  defaultColumnTypesTTL,
+				PrimaryIndex:    []string{"id", "name"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid config, schema evolution",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTable:           "test",
+				KeySchemaEvolution: "addColumns",
+			},
+			want: Destination{
+				General:         general,
+				MaxInsertRows:   defaultMaxInsertRows,
+				MaxInsertBytes:  defaultMaxInsertBytes,
+				WriteMode:       WriteModeInsert,
+				UpsertMethod:    UpsertMethodDeleteInsert,
+				TableType:       TableTypeFact,
+				SchemaEvolution: SchemaEvolutionAddColumns,
+				ColumnTypesTTL:  defaultColumnTypesTTL,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid config, unknown schema evolution mode",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTable:           "test",
+				KeySchemaEvolution: "dropColumns",
+			},
+			want:    Destination{},
+			wantErr: true,
+		},
+		{
+			name: "valid config, column types ttl",
+			cfg: map[string]string{
+				KeyEmail:          "test@test.com",
+				KeyPassword:       "12345",
+				KeyAccountName:    "super_account",
+				KeyEngineName:     "super_engine",
+				KeyDB:             "db",
+				KeyTable:          "test",
+				KeyColumnTypesTTL: "30s",
+			},
+			want: Destination{
+				General:         general,
+				MaxInsertRows:   defaultMaxInsertRows,
+				MaxInsertBytes:  defaultMaxInsertBytes,
+				WriteMode:       WriteModeInsert,
+				UpsertMethod:    UpsertMethodDeleteInsert,
+				TableType:       TableTypeFact,
+				SchemaEvolution: SchemaEvolutionNone,
+				ColumnTypesTTL:  30 * time.Second,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid config, negative column types ttl",
+			cfg: map[string]string{
+				KeyEmail:          "test@test.com",
+				KeyPassword:       "12345",
+				KeyAccountName:    "super_account",
+				KeyEngineName:     "super_engine",
+				KeyDB:             "db",
+				KeyTable:          "test",
+				KeyColumnTypesTTL: "-1m",
+			},
+			want:    Destination{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, autoCreateTable is not bool",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTable:           "test",
+				KeyAutoCreateTable: "yes please",
+			},
+			want:    Destination{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, unknown table type",
+			cfg: map[string]string{
+				KeyEmail:       "test@test.com",
+				KeyPassword:    "12345",
+				KeyAccountName: "super_account",
+				KeyEngineName:  "super_engine",
+				KeyDB:          "db",
+				KeyTable:       "test",
+				KeyTableType:   "aggregating",
+			},
+			want:    Destination{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, unknown write mode",
+			cfg: map[string]string{
+				KeyEmail:       "test@test.com",
+				KeyPassword:    "12345",
+				KeyAccountName: "super_account",
+				KeyEngineName:  "super_engine",
+				KeyDB:          "db",
+				KeyTable:       "test",
+				KeyWriteMode:   "replace",
+			},
+			want:    Destination{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, maxInsertRows is not int",
+			cfg: map[string]string{
+				KeyEmail:         "test@test.com",
+				KeyPassword:      "12345",
+				KeyAccountName:   "super_account",
+				KeyEngineName:    "super_engine",
+				KeyDB:            "db",
+				KeyTable:         "test",
+				KeyMaxInsertRows: "ten",
+			},
+			want:    Destination{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, maxInsertBytes is less than 1",
+			cfg: map[string]string{
+				KeyEmail:          "test@test.com",
+				KeyPassword:       "12345",
+				KeyAccountName:    "super_account",
+				KeyEngineName:     "super_engine",
+				KeyDB:             "db",
+				KeyTable:          "test",
+				KeyMaxInsertBytes: "0",
+			},
+			want:    Destination{},
+			wantErr: true,
+		},
+		{
+			name: "valid config, table settings",
+			cfg: map[string]string{
+				KeyEmail:                         "test@test.com",
+				KeyPassword:                      "12345",
+				KeyAccountName:                   "super_account",
+				KeyEngineName:                    "super_engine",
+				KeyDB:                            "db",
+				KeyTable:                         "test",
+				KeyKeyColumns:                    "id",
+				"tables.events.keyColumns":       "event_id,day",
+				"tables.events.writeMode":        "upsert",
+				"tables.events.autoCreateTable":  "true",
+				"tables.events.tableType":        "Dimension",
+				"tables.public.orders.writeMode": "upsert",
+			},
+			want: Destination{
+				General:         general,
+				KeyColumns:      []string{"id"},
+				MaxInsertRows:   defaultMaxInsertRows,
+				MaxInsertBytes:  defaultMaxInsertBytes,
+				WriteMode:       WriteModeInsert,
+				UpsertMethod:    UpsertMethodDeleteInsert,
+				TableType:       TableTypeFact,
+				SchemaEvolution: SchemaEvolutionNone,
+				ColumnTypesTTL:  defaultColumnTypesTTL,
+				TableDestinations: map[string]TableDestination{
+					"events": {
+						KeyColumns:      []string{"event_id", "day"},
+						WriteMode:       WriteModeUpsert,
+						UpsertMethod:    UpsertMethodDeleteInsert,
+						AutoCreateTable: true,
+						TableType:       TableTypeDimension,
+					},
+					"public.orders": {
+						KeyColumns:   []string{"id"},
+						WriteMode:    WriteModeUpsert,
+						UpsertMethod: UpsertMethodDeleteInsert,
+						TableType:    TableTypeFact,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid config, unknown table setting",
+			cfg: map[string]string{
+				KeyEmail:                  "test@test.com",
+				KeyPassword:               "12345",
+				KeyAccountName:            "super_account",
+				KeyEngineName:             "super_engine",
+				KeyDB:                     "db",
+				KeyTable:                  "test",
+				"tables.events.batchSize": "10",
+			},
+			want:    Destination{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, unknown table write mode",
+			cfg: map[string]string{
+				KeyEmail:                  "test@test.com",
+				KeyPassword:               "12345",
+				KeyAccountName:            "super_account",
+				KeyEngineName:             "super_engine",
+				KeyDB:                     "db",
+				KeyTable:                  "test",
+				"tables.events.writeMode": "replace",
+			},
+			want:    Destination{},
+			wantErr: true,
+		},
+		{
+			name: "valid config, table template",
+			cfg: map[string]string{
+				KeyEmail:         "test@test.com",
+				KeyPassword:      "12345",
+				KeyAccountName:   "super_account",
+				KeyEngineName:    "super_engine",
+				KeyDB:            "db",
+				KeyTable:         "test",
+				KeyTableTemplate: ` {{ index .Metadata "opencdc.collection" }}_raw `,
+			},
+			want: Destination{
+				General:         general,
+				MaxInsertRows:   defaultMaxInsertRows,
+				MaxInsertBytes:  defaultMaxInsertBytes,
+				WriteMode:       WriteModeInsert,
+				UpsertMethod:    UpsertMethodDeleteInsert,
+				TableType:       TableTypeFact,
+				SchemaEvolution: SchemaEvolutionNone,
+				ColumnTypesTTL:  defaultColumnTypesTTL,
+				TableTemplate:   `{{ index .Metadata "opencdc.collection" }}_raw`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid config, table template syntax error",
+			cfg: map[string]string{
+				KeyEmail:         "test@test.com",
+				KeyPassword:      "12345",
+				KeyAccountName:   "super_account",
+				KeyEngineName:    "super_engine",
+				KeyDB:            "db",
+				KeyTable:         "test",
+				KeyTableTemplate: `{{ index .Metadata "opencdc.collection" }`,
+			},
+			want:    Destination{},
+			wantErr: true,
+		},
+		{
+			name: "valid config, column mapping",
+			cfg: map[string]string{
+				KeyEmail:                      "test@test.com",
+				KeyPassword:                   "12345",
+				KeyAccountName:                "super_account",
+				KeyEngineName:                 "super_engine",
+				KeyDB:                         "db",
+				KeyTable:                      "test",
+				KeyColumnMapping:              "userName:user_name, address.city : city",
+				KeyExcludeFields:              "password,address.zip",
+				"tables.events.includeFields": "id,payload.type",
+			},
+			want: Destination{
+				General:         general,
+				MaxInsertRows:   defaultMaxInsertRows,
+				MaxInsertBytes:  defaultMaxInsertBytes,
+				WriteMode:       WriteModeInsert,
+				UpsertMethod:    UpsertMethodDeleteInsert,
+				TableType:       TableTypeFact,
+				SchemaEvolution: SchemaEvolutionNone,
+				ColumnTypesTTL:  defaultColumnTypesTTL,
+				ColumnMapping: ColumnMapping{
+					Columns:       map[string]string{"userName": "user_name", "address.city": "city"},
+					ExcludeFields: []string{"password", "address.zip"},
+				},
+				TableDestinations: map[string]TableDestination{
+					"events": {
+						WriteMode:    WriteModeInsert,
+						UpsertMethod: UpsertMethodDeleteInsert,
+						TableType:    TableTypeFact,
+						ColumnMapping: ColumnMapping{
+							Columns:       map[string]string{"userName": "user_name", "address.city": "city"},
+							IncludeFields: []string{"id", "payload.type"},
+							ExcludeFields: []string{"password", "address.zip"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid config, column mapping without column",
+			cfg: map[string]string{
+				KeyEmail:         "test@test.com",
+				KeyPassword:      "12345",
+				KeyAccountName:   "super_account",
+				KeyEngineName:    "super_engine",
+				KeyDB:            "db",
+				KeyTable:         "test",
+				KeyColumnMapping: "userName:user_name,address.city",
+			},
+			want:    Destination{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, field mapped twice",
+			cfg: map[string]string{
+				KeyEmail:         "test@test.com",
+				KeyPassword:      "12345",
+				KeyAccountName:   "super_account",
+				KeyEngineName:    "super_engine",
+				KeyDB:            "db",
+				KeyTable:         "test",
+				KeyColumnMapping: "userName:user_name,userName:name",
+			},
+			want:    Destination{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, missed table",
+			cfg: map[string]string{
+				KeyEmail:       "test@test.com",
+				KeyPassword:    "12345",
+				KeyAccountName: "super_account",
+				KeyEngineName:  "super_engine",
+				KeyDB:          "db",
+			},
+			want:    Destination{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDestination(tt.cfg)
+			if err != nil && !tt.wantErr {
+				t.Errorf("parse error = \"%s\", wantErr %t", err.Error(), tt.wantErr)
+
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parse = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
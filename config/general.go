@@ -15,6 +15,7 @@
 package config
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/conduitio-labs/conduit-connector-firebolt/config/validator"
@@ -25,6 +26,10 @@ const (
 	KeyEmail string = "email"
 	// KeyPassword is a config name for a password.
 	KeyPassword string = "password"
+	// KeyClientID is a config name for a service account client id.
+	KeyClientID string = "clientId"
+	// KeyClientSecret is a config name for a service account client secret.
+	KeyClientSecret string = "clientSecret"
 	// KeyAccountName is a config name for an account name.
 	KeyAccountName string = "accountName"
 	// KeyEngineName is a config name for an engine name.
@@ -33,39 +38,103 @@ const (
 	KeyDB string = "db"
 	// KeyTable is a config name for a table.
 	KeyTable string = "table"
+	// KeyAPIURL is a config name for a Firebolt API base URL.
+	KeyAPIURL string = "apiURL"
+	// KeyEngineURL is a config name for an engine endpoint.
+	KeyEngineURL string = "engineURL"
+)
+
+var (
+	// errMissingCredentials occurs when neither user nor service account credentials are set.
+	errMissingCredentials = fmt.Errorf("either %q and %q or %q and %q config values must be set",
+		KeyEmail, KeyPassword, KeyClientID, KeyClientSecret)
+	// errConflictingCredentials occurs when both user and service account credentials are set.
+	errConflictingCredentials = fmt.Errorf("%q and %q config values cannot be used together with %q and %q",
+		KeyEmail, KeyPassword, KeyClientID, KeyClientSecret)
 )
 
 // General represents configuration needed for Firebolt.
 // This values are shared between source and destination.
 type General struct {
 	// Email Firebolt account email.
-	Email string `validate:"required,email"`
+	Email string `validate:"omitempty,email"`
 	// Password Firebolt account password.
-	Password string `validate:"required"`
+	Password string
+	// ClientID is a Firebolt service account client id.
+	ClientID string
+	// ClientSecret is a Firebolt service account client secret.
+	ClientSecret string
 	// AccountName is a Firebolt account name.
-	AccountName string `validate:"required"`
+	AccountName string `validate:"required_without=EngineURL"`
 	// EngineName is a Firebolt engine name.
-	EngineName string `validate:"required"`
+	EngineName string `validate:"required_without=EngineURL"`
 	// DB - database name.
 	DB string `validate:"required"`
-	// Table - database table name.
-	Table string `validate:"required"`
+	// Table - database table name, the source may read the result of a query instead.
+	Table string
+	// APIURL is a Firebolt API base URL, the client uses its default URL if it's empty.
+	APIURL string `validate:"omitempty,url"`
+	// EngineURL is an engine endpoint, if it's set the connector skips account and engine discovery.
+	EngineURL string
 }
 
 // Parse attempts to parse plugins.Config into a General struct.
 func ParseGeneral(cfg map[string]string) (General, error) {
 	general := General{
-		Email:       strings.ToLower(cfg[KeyEmail]),
-		Password:    cfg[KeyPassword],
-		AccountName: cfg[KeyAccountName],
-		EngineName:  cfg[KeyEngineName],
-		DB:          cfg[KeyDB],
-		Table:       cfg[KeyTable],
+		Email:        strings.ToLower(cfg[KeyEmail]),
+		Password:     cfg[KeyPassword],
+		ClientID:     cfg[KeyClientID],
+		ClientSecret: cfg[KeyClientSecret],
+		AccountName:  cfg[KeyAccountName],
+		EngineName:   cfg[KeyEngineName],
+		DB:           cfg[KeyDB],
+		Table:        cfg[KeyTable],
+		APIURL:       cfg[KeyAPIURL],
+		EngineURL:    cfg[KeyEngineURL],
 	}
 
 	if err := validator.Validate(general); err != nil {
 		return General{}, err
 	}
 
+	if err := general.validateCredentials(); err != nil {
+		return General{}, err
+	}
+
 	return general, nil
 }
+
+// validateCredentials checks that exactly one credential style is configured:
+// either a user's email and password or a service account's client id and secret.
+func (g General) validateCredentials() error {
+	hasUser := g.Email != "" || g.Password != ""
+	hasServiceAccount := g.ClientID != "" || g.ClientSecret != ""
+
+	switch {
+	case hasUser && hasServiceAccount:
+		return errConflictingCredentials
+	case hasUser:
+		return requireValues(configValue{KeyEmail, g.Email}, configValue{KeyPassword, g.Password})
+	case hasServiceAccount:
+		return requireValues(configValue{KeyClientID, g.ClientID}, configValue{KeyClientSecret, g.ClientSecret})
+	default:
+		return errMissingCredentials
+	}
+}
+
+// configValue is a config value with its key.
+type configValue struct {
+	key   string
+	value string
+}
+
+// requireValues returns an error naming the first of the provided config values which is empty.
+func requireValues(values ...configValue) error {
+	for _, v := range values {
+		if v.value == "" {
+			return fmt.Errorf("%q config value must be set", v.key)
+		}
+	}
+
+	return nil
+}
@@ -46,6 +46,110 @@ func TestParseGeneral(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid config, service account",
+			cfg: map[string]string{
+				KeyClientID:     "client_id",
+				KeyClientSecret: "client_secret",
+				KeyAccountName:  "super_account",
+				KeyEngineName:   "super_engine",
+				KeyDB:           "db",
+				KeyTable:        "test",
+			},
+			want: General{
+				ClientID:     "client_id",
+				ClientSecret: "client_secret",
+				AccountName:  "super_account",
+				EngineName:   "super_engine",
+				DB:           "db",
+				Table:        "test",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid config, missed client secret",
+			cfg: map[string]string{
+				KeyClientID:    "client_id",
+				KeyAccountName: "super_account",
+				KeyEngineName:  "super_engine",
+				KeyDB:          "db",
+				KeyTable:       "test",
+			},
+			want:    General{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, both email and service account",
+			cfg: map[string]string{
+				KeyEmail:        "test@test.com",
+				KeyPassword:     "12345",
+				KeyClientID:     "client_id",
+				KeyClientSecret: "client_secret",
+				KeyAccountName:  "super_account",
+				KeyEngineName:   "super_engine",
+				KeyDB:           "db",
+				KeyTable:        "test",
+			},
+			want:    General{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, missed credentials",
+			cfg: map[string]string{
+				KeyAccountName: "super_account",
+				KeyEngineName:  "super_engine",
+				KeyDB:          "db",
+				KeyTable:       "test",
+			},
+			want:    General{},
+			wantErr: true,
+		},
+		{
+			name: "valid config, engine url",
+			cfg: map[string]string{
+				KeyEmail:     "test@test.com",
+				KeyPassword:  "12345",
+				KeyDB:        "db",
+				KeyTable:     "test",
+				KeyAPIURL:    "http://localhost:8080",
+				KeyEngineURL: "http://localhost:8081",
+			},
+			want: General{
+				Email:     "test@test.com",
+				Password:  "12345",
+				DB:        "db",
+				Table:     "test",
+				APIURL:    "http://localhost:8080",
+				EngineURL: "http://localhost:8081",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid config, missed engine name",
+			cfg: map[string]string{
+				KeyEmail:       "test@test.com",
+				KeyPassword:    "12345",
+				KeyAccountName: "super_account",
+				KeyDB:          "db",
+				KeyTable:       "test",
+			},
+			want:    General{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, invalid api url",
+			cfg: map[string]string{
+				KeyEmail:       "test@test.com",
+				KeyPassword:    "12345",
+				KeyAccountName: "super_account",
+				KeyEngineName:  "super_engine",
+				KeyDB:          "db",
+				KeyTable:       "test",
+				KeyAPIURL:      "not a url",
+			},
+			want:    General{},
+			wantErr: true,
+		},
 		{
 			name: "invalid config, missed email",
 			cfg: map[string]string{
@@ -112,3 +216,15 @@ func TestParseGeneral(t *testing.T) {
 		})
 	}
 }
+
+func TestRequireValues(t *testing.T) {
+	// the error names the first missing value.
+	err := requireValues(configValue{KeyEmail, ""}, configValue{KeyPassword, ""}, configValue{KeyDB, ""})
+	if want := `"email" config value must be set`; err == nil || err.Error() != want {
+		t.Errorf("require values error = %v, want %s", err, want)
+	}
+
+	if err = requireValues(configValue{KeyEmail, "test@test.com"}); err != nil {
+		t.Errorf("require values error = %v, want nil", err)
+	}
+}
@@ -32,9 +32,41 @@ const (
 	KeyPrimaryKeys string = "primaryKeys"
 	// KeyOrderingColumns is a config name for the orderingColumns field.
 	KeyOrderingColumns = "orderingColumns"
+	// KeyTrackingColumn is a config name for the trackingColumn field.
+	KeyTrackingColumn = "trackingColumn"
+	// KeyPayloadFormat is a config name for the payloadFormat field.
+	KeyPayloadFormat = "payloadFormat"
+	// KeyQuery is a config name for the query field.
+	KeyQuery = "query"
+	// KeyFilter is a config name for the filter field.
+	KeyFilter = "filter"
+	// KeySnapshotPartitions is a config name for the snapshotPartitions field.
+	KeySnapshotPartitions = "snapshotPartitions"
+	// KeyTableIteration is a config name for the tableIteration field.
+	KeyTableIteration = "tableIteration"
+
+	// PayloadFormatStructured means the record payloads are structured data with typed values.
+	PayloadFormatStructured = "structured"
+	// PayloadFormatRaw means the record payloads are rows encoded as JSON.
+	PayloadFormatRaw = "raw"
+
+	// TableIterationRoundRobin means the source reads a record of each table in turn.
+	TableIterationRoundRobin = "roundRobin"
+	// TableIterationSequential means the source reads all the available records of a table before the next one.
+	TableIterationSequential = "sequential"
 
 	// defaultBatchSize is a default batch size.
 	defaultBatchSize = 100
+	// defaultSnapshotPartitions is a default number of the snapshot partitions, the snapshot isn't partitioned.
+	defaultSnapshotPartitions = 1
+)
+
+var (
+	// errMissingTableOrQuery occurs when none of the table, the tables and the query is set.
+	errMissingTableOrQuery = fmt.Errorf("one of %q, %q or %q config values must be set", KeyTable, KeyTables, KeyQuery)
+	// errConflictingTableAndQuery occurs when more than one of the table, the tables and the query is set.
+	errConflictingTableAndQuery = fmt.Errorf("only one of %q, %q and %q config values can be set",
+		KeyTable, KeyTables, KeyQuery)
 )
 
 // Source holds source-related configurable values.
@@ -48,7 +80,37 @@ type Source struct {
 	// PrimaryKeys - columns names that records should use for their `Key` fields.
 	PrimaryKeys []string
 	// OrderingColumns is a name of columns that the connector will use for ordering rows.
-	OrderingColumns []string `key:"orderingColumns" validate:"required"`
+	// It's required unless the Tables are set, their ordering columns may be set by the TableSources.
+	OrderingColumns []string `key:"orderingColumns"`
+	// TrackingColumn is a name of the column which value increases when a row is inserted or updated.
+	// The source captures the changes after the snapshot is finished, by default it's the first ordering column.
+	TrackingColumn string
+	// PayloadFormat - the format of the record payloads, structured data or JSON encoded rows.
+	PayloadFormat string
+	// Query - a SELECT query which result is read instead of the table.
+	Query string
+	// Filter - a WHERE clause predicate limiting the read rows.
+	Filter string
+	// SnapshotPartitions - the number of ranges of the first ordering column values read concurrently by the snapshot.
+	SnapshotPartitions int `validate:"gte=1,lte=32"`
+	// Tables - names, glob patterns or regular expressions of the tables read by one source.
+	Tables []string
+	// TableIteration - the way the tables are iterated, TableIterationRoundRobin or TableIterationSequential.
+	TableIteration string
+	// TableSources - the settings of the tables by their names, they override the top-level ones.
+	TableSources map[string]TableSource
+}
+
+// TableSource holds the settings of a table read by the source.
+type TableSource struct {
+	// Columns - list of columns to read from the table, all the columns are read if it's empty.
+	Columns []string
+	// PrimaryKeys - names of the columns that records should use for their `Key` fields.
+	PrimaryKeys []string
+	// OrderingColumns - names of the columns that the source uses for ordering rows.
+	OrderingColumns []string
+	// TrackingColumn - name of the column which value increases when a row is inserted or updated.
+	TrackingColumn string
 }
 
 // ParseSource attempts to parse plugins.Config into a Source struct.
@@ -59,8 +121,17 @@ func ParseSource(cfg map[string]string) (Source, error) {
 	}
 
 	source := Source{
-		General:   general,
-		BatchSize: defaultBatchSize,
+		General:            general,
+		BatchSize:          defaultBatchSize,
+		PayloadFormat:      PayloadFormatStructured,
+		SnapshotPartitions: defaultSnapshotPartitions,
+	}
+
+	source.Query = strings.TrimSpace(cfg[KeyQuery])
+	source.Filter = strings.TrimSpace(cfg[KeyFilter])
+
+	if cfg[KeyPayloadFormat] != "" {
+		source.PayloadFormat = strings.ToLower(cfg[KeyPayloadFormat])
 	}
 
 	if colsRaw := cfg[KeyColumns]; colsRaw != "" {
@@ -75,6 +146,11 @@ func ParseSource(cfg map[string]string) (Source, error) {
 		source.OrderingColumns = strings.Split(colsRaw, ",")
 	}
 
+	source.TrackingColumn = cfg[KeyTrackingColumn]
+	if source.TrackingColumn == "" && len(source.OrderingColumns) > 0 {
+		source.TrackingColumn = source.OrderingColumns[0]
+	}
+
 	if cfg[KeyBatchSize] != "" {
 		batchSize, er := strconv.Atoi(cfg[KeyBatchSize])
 		if er != nil {
@@ -84,9 +160,191 @@ func ParseSource(cfg map[string]string) (Source, error) {
 		source.BatchSize = batchSize
 	}
 
-	if err = validator.Validate(source); err != nil {
+	source.SnapshotPartitions, err = parseInt(cfg, KeySnapshotPartitions, source.SnapshotPartitions)
+	if err != nil {
+		return Source{}, err
+	}
+
+	if err = source.parseTables(cfg); err != nil {
+		return Source{}, err
+	}
+
+	if err = source.validate(); err != nil {
 		return Source{}, err
 	}
 
 	return source, nil
 }
+
+// parseTables parses the tables read by one source, the way they're iterated and their settings.
+func (s *Source) parseTables(cfg map[string]string) error {
+	var err error
+
+	if s.Tables, err = parseTablePatterns(cfg[KeyTables]); err != nil {
+		return err
+	}
+
+	if len(s.Tables) > 0 {
+		s.TableIteration = TableIterationRoundRobin
+		if cfg[KeyTableIteration] != "" {
+			s.TableIteration = cfg[KeyTableIteration]
+		}
+	}
+
+	if s.TableSources, err = s.parseTableSources(cfg); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validate checks the parsed config values.
+func (s Source) validate() error {
+	if err := validator.Validate(s); err != nil {
+		return err
+	}
+
+	if err := s.validateTableOrQuery(); err != nil {
+		return err
+	}
+
+	if len(s.Tables) == 0 && len(s.OrderingColumns) == 0 {
+		return fmt.Errorf("%q config value must be set", KeyOrderingColumns)
+	}
+
+	if err := validateColumns(s.Columns, s.OrderingColumns, s.TrackingColumn); err != nil {
+		return err
+	}
+
+	if s.TableIteration != "" {
+		err := validateOneOf(KeyTableIteration, s.TableIteration, TableIterationRoundRobin, TableIterationSequential)
+		if err != nil {
+			return err
+		}
+	}
+
+	return validateOneOf(KeyPayloadFormat, s.PayloadFormat, PayloadFormatStructured, PayloadFormatRaw)
+}
+
+// TableSource returns the settings of the table, they're the top-level ones unless the table has its own settings.
+func (s Source) TableSource(table string) (TableSource, error) {
+	if tableSource, ok := s.TableSources[table]; ok {
+		return tableSource, nil
+	}
+
+	if len(s.OrderingColumns) == 0 {
+		return TableSource{}, fmt.Errorf("either %q or %q config value must be set",
+			KeyOrderingColumns, tableKeyPrefix+table+"."+KeyOrderingColumns)
+	}
+
+	return TableSource{
+		Columns:         s.Columns,
+		PrimaryKeys:     s.PrimaryKeys,
+		OrderingColumns: s.OrderingColumns,
+		TrackingColumn:  s.TrackingColumn,
+	}, nil
+}
+
+// parseTableSources parses the settings of the tables, the settings which are not set for a table
+// are the top-level ones. The tracking column is the first ordering column of the table by default.
+func (s Source) parseTableSources(cfg map[string]string) (map[string]TableSource, error) {
+	settings, err := parseTableSettings(cfg, KeyColumns, KeyPrimaryKeys, KeyOrderingColumns, KeyTrackingColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(settings) > 0 && len(s.Tables) == 0 {
+		return nil, fmt.Errorf("%q config values can be set only with the %q", tableKeyPrefix+"*", KeyTables)
+	}
+
+	var tableSources map[string]TableSource
+
+	for table, values := range settings {
+		tableSource := TableSource{
+			Columns:         s.Columns,
+			PrimaryKeys:     s.PrimaryKeys,
+			OrderingColumns: s.OrderingColumns,
+			TrackingColumn:  cfg[KeyTrackingColumn],
+		}
+
+		for name, list := range map[string]*[]string{
+			KeyColumns:         &tableSource.Columns,
+			KeyPrimaryKeys:     &tableSource.PrimaryKeys,
+			KeyOrderingColumns: &tableSource.OrderingColumns,
+		} {
+			if values[name] != "" {
+				*list = strings.Split(values[name], ",")
+			}
+		}
+
+		if values[KeyTrackingColumn] != "" {
+			tableSource.TrackingColumn = values[KeyTrackingColumn]
+		}
+
+		if tableSource.TrackingColumn == "" && len(tableSource.OrderingColumns) > 0 {
+			tableSource.TrackingColumn = tableSource.OrderingColumns[0]
+		}
+
+		if len(tableSource.OrderingColumns) == 0 {
+			return nil, fmt.Errorf("%q config value must be set", tableKeyPrefix+table+"."+KeyOrderingColumns)
+		}
+
+		err = validateColumns(tableSource.Columns, tableSource.OrderingColumns, tableSource.TrackingColumn)
+		if err != nil {
+			return nil, fmt.Errorf("table %q: %w", table, err)
+		}
+
+		if tableSources == nil {
+			tableSources = make(map[string]TableSource)
+		}
+
+		tableSources[table] = tableSource
+	}
+
+	return tableSources, nil
+}
+
+// validateTableOrQuery checks that the source reads exactly one of the table, the tables or the result of the query.
+func (s Source) validateTableOrQuery() error {
+	var count int
+
+	for _, set := range []bool{s.Table != "", len(s.Tables) > 0, s.Query != ""} {
+		if set {
+			count++
+		}
+	}
+
+	switch count {
+	case 0:
+		return errMissingTableOrQuery
+	case 1:
+		return nil
+	default:
+		return errConflictingTableAndQuery
+	}
+}
+
+// validateColumns checks that the read columns include the ordering and tracking columns,
+// their values are needed to continue reading after the last processed row.
+func validateColumns(readColumns, orderingColumns []string, trackingColumn string) error {
+	if len(readColumns) == 0 {
+		return nil
+	}
+
+	columns := make(map[string]struct{}, len(readColumns))
+	for _, col := range readColumns {
+		columns[strings.ToLower(col)] = struct{}{}
+	}
+
+	for _, col := range orderingColumns {
+		if _, ok := columns[strings.ToLower(col)]; !ok {
+			return fmt.Errorf("%q config value must contain all the %q, missed %q", KeyColumns, KeyOrderingColumns, col)
+		}
+	}
+
+	if _, ok := columns[strings.ToLower(trackingColumn)]; trackingColumn != "" && !ok {
+		return fmt.Errorf("%q config value must contain the %q", KeyColumns, KeyTrackingColumn)
+	}
+
+	return nil
+}
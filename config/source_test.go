@@ -48,9 +48,12 @@ func TestParseSource(t *testing.T) {
 					DB:          "db",
 					Table:       "test",
 				},
-				BatchSize:       100,
-				PrimaryKeys:     []string{"id"},
-				OrderingColumns: []string{"id"},
+				BatchSize:          100,
+				PrimaryKeys:        []string{"id"},
+				OrderingColumns:    []string{"id"},
+				TrackingColumn:     "id",
+				PayloadFormat:      PayloadFormatStructured,
+				SnapshotPartitions: defaultSnapshotPartitions,
 			},
 			wantErr: false,
 		},
@@ -75,8 +78,11 @@ func TestParseSource(t *testing.T) {
 					DB:          "db",
 					Table:       "test",
 				},
-				BatchSize:       20,
-				OrderingColumns: []string{"id"},
+				BatchSize:          20,
+				OrderingColumns:    []string{"id"},
+				TrackingColumn:     "id",
+				PayloadFormat:      PayloadFormatStructured,
+				SnapshotPartitions: defaultSnapshotPartitions,
 			},
 			wantErr: false,
 		},
@@ -103,10 +109,13 @@ func TestParseSource(t *testing.T) {
 					DB:          "db",
 					Table:       "test",
 				},
-				BatchSize:       20,
-				Columns:         []string{"id", "name"},
-				PrimaryKeys:     []string{"id", "name"},
-				OrderingColumns: []string{"id", "name"},
+				BatchSize:          20,
+				Columns:            []string{"id", "name"},
+				PrimaryKeys:        []string{"id", "name"},
+				OrderingColumns:    []string{"id", "name"},
+				TrackingColumn:     "id",
+				PayloadFormat:      PayloadFormatStructured,
+				SnapshotPartitions: defaultSnapshotPartitions,
 			},
 			wantErr: false,
 		},
@@ -125,6 +134,361 @@ func TestParseSource(t *testing.T) {
 			want:    Source{},
 			wantErr: true,
 		},
+		{
+			name: "valid config, tracking column",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTable:           "test",
+				KeyColumns:         "id,updated_at",
+				KeyOrderingColumns: "id",
+				KeyTrackingColumn:  "updated_at",
+			},
+			want: Source{
+				General: General{
+					Email:       "test@test.com",
+					Password:    "12345",
+					AccountName: "super_account",
+					EngineName:  "super_engine",
+					DB:          "db",
+					Table:       "test",
+				},
+				BatchSize:          defaultBatchSize,
+				Columns:            []string{"id", "updated_at"},
+				OrderingColumns:    []string{"id"},
+				TrackingColumn:     "updated_at",
+				PayloadFormat:      PayloadFormatStructured,
+				SnapshotPartitions: defaultSnapshotPartitions,
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid config, raw payload format",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTable:           "test",
+				KeyOrderingColumns: "id",
+				KeyPayloadFormat:   "RAW",
+			},
+			want: Source{
+				General: General{
+					Email:       "test@test.com",
+					Password:    "12345",
+					AccountName: "super_account",
+					EngineName:  "super_engine",
+					DB:          "db",
+					Table:       "test",
+				},
+				BatchSize:          defaultBatchSize,
+				OrderingColumns:    []string{"id"},
+				TrackingColumn:     "id",
+				PayloadFormat:      PayloadFormatRaw,
+				SnapshotPartitions: defaultSnapshotPartitions,
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid config, query",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyQuery:           " SELECT id, name FROM users WHERE id > 10 ",
+				KeyOrderingColumns: "id",
+			},
+			want: Source{
+				General: General{
+					Email:       "test@test.com",
+					Password:    "12345",
+					AccountName: "super_account",
+					EngineName:  "super_engine",
+					DB:          "db",
+				},
+				BatchSize:          defaultBatchSize,
+				OrderingColumns:    []string{"id"},
+				TrackingColumn:     "id",
+				PayloadFormat:      PayloadFormatStructured,
+				SnapshotPartitions: defaultSnapshotPartitions,
+				Query:              "SELECT id, name FROM users WHERE id > 10",
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid config, filter",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTable:           "test",
+				KeyOrderingColumns: "id",
+				KeyFilter:          "tenant_id = 42 ",
+			},
+			want: Source{
+				General: General{
+					Email:       "test@test.com",
+					Password:    "12345",
+					AccountName: "super_account",
+					EngineName:  "super_engine",
+					DB:          "db",
+					Table:       "test",
+				},
+				BatchSize:          defaultBatchSize,
+				OrderingColumns:    []string{"id"},
+				TrackingColumn:     "id",
+				PayloadFormat:      PayloadFormatStructured,
+				SnapshotPartitions: defaultSnapshotPartitions,
+				Filter:             "tenant_id = 42",
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid config, snapshot partitions",
+			cfg: map[string]string{
+				KeyEmail:              "test@test.com",
+				KeyPassword:           "12345",
+				KeyAccountName:        "super_account",
+				KeyEngineName:         "super_engine",
+				KeyDB:                 "db",
+				KeyTable:              "test",
+				KeyOrderingColumns:    "id",
+				KeySnapshotPartitions: "8",
+			},
+			want: Source{
+				General: General{
+					Email:       "test@test.com",
+					Password:    "12345",
+					AccountName: "super_account",
+					EngineName:  "super_engine",
+					DB:          "db",
+					Table:       "test",
+				},
+				BatchSize:          defaultBatchSize,
+				OrderingColumns:    []string{"id"},
+				TrackingColumn:     "id",
+				PayloadFormat:      PayloadFormatStructured,
+				SnapshotPartitions: 8,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid config, too many snapshot partitions",
+			cfg: map[string]string{
+				KeyEmail:              "test@test.com",
+				KeyPassword:           "12345",
+				KeyAccountName:        "super_account",
+				KeyEngineName:         "super_engine",
+				KeyDB:                 "db",
+				KeyTable:              "test",
+				KeyOrderingColumns:    "id",
+				KeySnapshotPartitions: "100",
+			},
+			want:    Source{},
+			wantErr: true,
+		},
+		{
+			name: "valid config, tables",
+			cfg: map[string]string{
+				KeyEmail:                          "test@test.com",
+				KeyPassword:                       "12345",
+				KeyAccountName:                    "super_account",
+				KeyEngineName:                     "super_engine",
+				KeyDB:                             "db",
+				KeyTables:                         "users, sales_*,/events_\\d+/",
+				KeyOrderingColumns:                "id",
+				KeyTableIteration:                 TableIterationSequential,
+				"tables.users.primaryKeys":        "email",
+				"tables.events_1.columns":         "created_at,name",
+				"tables.events_1.orderingColumns": "created_at",
+			},
+			want: Source{
+				General: General{
+					Email:       "test@test.com",
+					Password:    "12345",
+					AccountName: "super_account",
+					EngineName:  "super_engine",
+					DB:          "db",
+				},
+				BatchSize:          defaultBatchSize,
+				OrderingColumns:    []string{"id"},
+				TrackingColumn:     "id",
+				PayloadFormat:      PayloadFormatStructured,
+				SnapshotPartitions: defaultSnapshotPartitions,
+				Tables:             []string{"users", "sales_*", "/events_\\d+/"},
+				TableIteration:     TableIterationSequential,
+				TableSources: map[string]TableSource{
+					"users": {
+						PrimaryKeys:     []string{"email"},
+						OrderingColumns: []string{"id"},
+						TrackingColumn:  "id",
+					},
+					"events_1": {
+						Columns:         []string{"created_at", "name"},
+						OrderingColumns: []string{"created_at"},
+						TrackingColumn:  "created_at",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid config, both table and tables",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTable:           "test",
+				KeyTables:          "users,orders",
+				KeyOrderingColumns: "id",
+			},
+			want:    Source{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, table settings without tables",
+			cfg: map[string]string{
+				KeyEmail:                  "test@test.com",
+				KeyPassword:               "12345",
+				KeyAccountName:            "super_account",
+				KeyEngineName:             "super_engine",
+				KeyDB:                     "db",
+				KeyTable:                  "test",
+				KeyOrderingColumns:        "id",
+				"tables.test.primaryKeys": "id",
+			},
+			want:    Source{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, unknown table setting",
+			cfg: map[string]string{
+				KeyEmail:                 "test@test.com",
+				KeyPassword:              "12345",
+				KeyAccountName:           "super_account",
+				KeyEngineName:            "super_engine",
+				KeyDB:                    "db",
+				KeyTables:                "users",
+				KeyOrderingColumns:       "id",
+				"tables.users.batchSize": "10",
+			},
+			want:    Source{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, invalid table regular expression",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTables:          "/users(/",
+				KeyOrderingColumns: "id",
+			},
+			want:    Source{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, unknown table iteration",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTables:          "users",
+				KeyOrderingColumns: "id",
+				KeyTableIteration:  "random",
+			},
+			want:    Source{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, both table and query",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTable:           "test",
+				KeyQuery:           "SELECT id FROM test",
+				KeyOrderingColumns: "id",
+			},
+			want:    Source{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, missed table and query",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyOrderingColumns: "id",
+			},
+			want:    Source{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, unknown payload format",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTable:           "test",
+				KeyOrderingColumns: "id",
+				KeyPayloadFormat:   "avro",
+			},
+			want:    Source{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, columns don't contain trackingColumn",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTable:           "test",
+				KeyColumns:         "id,name",
+				KeyOrderingColumns: "id",
+				KeyTrackingColumn:  "updated_at",
+			},
+			want:    Source{},
+			wantErr: true,
+		},
+		{
+			name: "invalid config, columns don't contain orderingColumns",
+			cfg: map[string]string{
+				KeyEmail:           "test@test.com",
+				KeyPassword:        "12345",
+				KeyAccountName:     "super_account",
+				KeyEngineName:      "super_engine",
+				KeyDB:              "db",
+				KeyTable:           "test",
+				KeyColumns:         "id,name",
+				KeyOrderingColumns: "created_at",
+			},
+			want:    Source{},
+			wantErr: true,
+		},
 		{
 			name: "invalid config, missed orderingColumns field",
 			cfg: map[string]string{
@@ -0,0 +1,156 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// KeyTables is a config name for the tables field.
+	KeyTables = "tables"
+
+	// tableKeyPrefix is a prefix of the config names of the table settings, e.g. tables.users.orderingColumns.
+	tableKeyPrefix = KeyTables + "."
+)
+
+// parseTableSettings returns the config values of the table settings by the table names and the setting names.
+// It returns an error if a setting is not one of the allowed ones.
+func parseTableSettings(cfg map[string]string, allowed ...string) (map[string]map[string]string, error) {
+	var settings map[string]map[string]string
+
+	for key, value := range cfg {
+		rest, ok := strings.CutPrefix(key, tableKeyPrefix)
+		if !ok {
+			continue
+		}
+
+		// the setting name follows the last dot, so the table names may contain dots.
+		dot := strings.LastIndex(rest, ".")
+		if dot <= 0 || dot == len(rest)-1 {
+			return nil, fmt.Errorf("%q config name must be in the form %q", key, tableKeyPrefix+"<table>.<setting>")
+		}
+
+		table, name := rest[:dot], rest[dot+1:]
+		if !slices.Contains(allowed, name) {
+			return nil, fmt.Errorf("%q config name: the table setting must be one of %q", key, allowed)
+		}
+
+		if settings == nil {
+			settings = make(map[string]map[string]string)
+		}
+
+		if settings[table] == nil {
+			settings[table] = make(map[string]string)
+		}
+
+		settings[table][name] = value
+	}
+
+	return settings, nil
+}
+
+// parseTablePatterns splits the config value into the table names and patterns, and checks that the patterns are valid.
+// A pattern is either a glob pattern, e.g. sales_*, or a regular expression between slashes, e.g. /sales_\d+/.
+func parseTablePatterns(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	patterns := strings.Split(value, ",")
+
+	for i := range patterns {
+		patterns[i] = strings.TrimSpace(patterns[i])
+
+		if _, err := tableMatcher(patterns[i]); err != nil {
+			return nil, fmt.Errorf("%q config value: %w", KeyTables, err)
+		}
+	}
+
+	return patterns, nil
+}
+
+// MatchTables returns the tables matching the patterns in the order of the patterns, the tables matching
+// the same pattern are sorted by name. The names which are not patterns are returned as they are.
+func MatchTables(patterns, tables []string) ([]string, error) {
+	var matched []string
+
+	for _, pattern := range patterns {
+		match, err := tableMatcher(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		if match == nil {
+			matched = append(matched, pattern)
+
+			continue
+		}
+
+		var names []string
+
+		for _, table := range tables {
+			if match(table) {
+				names = append(names, table)
+			}
+		}
+
+		slices.Sort(names)
+		matched = append(matched, names...)
+	}
+
+	// a table matching several patterns is read once.
+	var unique []string
+
+	for _, table := range matched {
+		if !slices.Contains(unique, table) {
+			unique = append(unique, table)
+		}
+	}
+
+	return unique, nil
+}
+
+// tableMatcher returns a function reporting whether a table matches the pattern, or nil if it's a table name.
+func tableMatcher(pattern string) (func(table string) bool, error) {
+	switch {
+	case pattern == "":
+		return nil, errors.New("empty table name")
+	case len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/"):
+		re, err := regexp.Compile("^(?:" + pattern[1:len(pattern)-1] + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("table regular expression %q: %w", pattern, err)
+		}
+
+		return re.MatchString, nil
+	case strings.ContainsAny(pattern, "*?["):
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("table pattern %q: %w", pattern, err)
+		}
+
+		return func(table string) bool {
+			ok, _ := path.Match(pattern, table)
+
+			return ok
+		}, nil
+	default:
+		return nil, nil
+	}
+}
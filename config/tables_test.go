@@ -0,0 +1,111 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchTables(t *testing.T) {
+	tables := []string{"users", "sales_2023", "sales_2022", "events_1", "events_x", "orders"}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "names",
+			patterns: []string{"orders", "users"},
+			want:     []string{"orders", "users"},
+		},
+		{
+			name:     "glob pattern",
+			patterns: []string{"sales_*"},
+			want:     []string{"sales_2022", "sales_2023"},
+		},
+		{
+			name:     "regular expression",
+			patterns: []string{`/events_\d+/`},
+			want:     []string{"events_1"},
+		},
+		{
+			name:     "table matching several patterns",
+			patterns: []string{"sales_2023", "sales_*", "users"},
+			want:     []string{"sales_2023", "sales_2022", "users"},
+		},
+		{
+			name:     "no matching tables",
+			patterns: []string{"archive_*"},
+			want:     nil,
+		},
+		{
+			name:     "invalid glob pattern",
+			patterns: []string{"sales_["},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchTables(tt.patterns, tables)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("match error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSource_TableSource(t *testing.T) {
+	source := Source{
+		Columns:         []string{"id", "name"},
+		OrderingColumns: []string{"id"},
+		TrackingColumn:  "id",
+		TableSources: map[string]TableSource{
+			"events": {OrderingColumns: []string{"created_at"}, TrackingColumn: "created_at"},
+		},
+	}
+
+	got, err := source.TableSource("events")
+	if err != nil {
+		t.Fatalf("table source: %v", err)
+	}
+
+	want := TableSource{OrderingColumns: []string{"created_at"}, TrackingColumn: "created_at"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("table source = %v, want %v", got, want)
+	}
+
+	got, err = source.TableSource("users")
+	if err != nil {
+		t.Fatalf("table source: %v", err)
+	}
+
+	want = TableSource{Columns: []string{"id", "name"}, OrderingColumns: []string{"id"}, TrackingColumn: "id"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("table source = %v, want %v", got, want)
+	}
+
+	// the ordering columns are required for the tables without their own settings.
+	if _, err = (Source{}).TableSource("users"); err == nil {
+		t.Error("table source error = nil, want error")
+	}
+}
@@ -70,6 +70,30 @@ func registerTranslations(validate *validator.Validate, uniTranslator ut.Transla
 		return err
 	}
 
+	// register a custom translation for the required_without tag
+	err = validate.RegisterTranslation("required_without", uniTranslator, func(ut ut.Translator) error {
+		return ut.Add("required_without", "\"{0}\" config value must be set when \"{1}\" is not set", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("required_without", fe.Field(), fe.Param())
+
+		return strings.ToLower(t)
+	})
+	if err != nil {
+		return err
+	}
+
+	// register a custom translation for the url tag
+	err = validate.RegisterTranslation("url", uniTranslator, func(ut ut.Translator) error {
+		return ut.Add("url", "\"{0}\" config value must be a valid url", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("url", fe.Field())
+
+		return strings.ToLower(t)
+	})
+	if err != nil {
+		return err
+	}
+
 	// register a custom translation for the max tag
 	err = validate.RegisterTranslation("email", uniTranslator, func(ut ut.Translator) error {
 		return ut.Add("email", "\"{0}\" config value must be a valid url", true)
@@ -28,7 +28,9 @@ import (
 
 // Writer defines a writer interface needed for the Destination.
 type Writer interface {
-	InsertRecord(ctx context.Context, record sdk.Record) error
+	InsertRecords(ctx context.Context, records []sdk.Record) (int, error)
+	UpdateRecord(ctx context.Context, record sdk.Record) error
+	DeleteRecord(ctx context.Context, record sdk.Record) error
 	SetColumnTypes(cl map[string]string)
 	Close(ctx context.Context) error
 }
@@ -52,23 +54,36 @@ func (d *Destination) Parameters() map[string]sdk.Parameter {
 	return map[string]sdk.Parameter{
 		config.KeyEmail: {
 			Default:     "",
-			Required:    true,
-			Description: "The Firebolt email account.",
+			Description: "The Firebolt email account. Required unless a service account is used.",
 		},
 		config.KeyPassword: {
 			Default:     "",
-			Required:    true,
-			Description: "The Firebolt account password.",
+			Description: "The Firebolt account password. Required unless a service account is used.",
+		},
+		config.KeyClientID: {
+			Default:     "",
+			Description: "The Firebolt service account client id. Required unless an email is used.",
+		},
+		config.KeyClientSecret: {
+			Default:     "",
+			Description: "The Firebolt service account client secret. Required unless an email is used.",
 		},
 		config.KeyAccountName: {
 			Default:     "",
-			Required:    true,
-			Description: "The Firebolt account name.",
+			Description: "The Firebolt account name. Required unless engineURL is set.",
 		},
 		config.KeyEngineName: {
 			Default:     "",
-			Required:    true,
-			Description: "The Firebolt engine name.",
+			Description: "The Firebolt engine name. Required unless engineURL is set.",
+		},
+		config.KeyAPIURL: {
+			Default:     client.DefaultAPIURL,
+			Description: "The Firebolt API base URL.",
+		},
+		config.KeyEngineURL: {
+			Default: "",
+			Description: "The Firebolt engine endpoint. If it's set the connector doesn't look up " +
+				"the account and the engine and doesn't start the engine.",
 		},
 		config.KeyDB: {
 			Default:     "",
@@ -76,9 +91,79 @@ func (d *Destination) Parameters() map[string]sdk.Parameter {
 			Description: "The Firebolt database name.",
 		},
 		config.KeyTable: {
+			Default:  "",
+			Required: true,
+			Description: "The Firebolt database table name. The keyColumns, writeMode, upsertMethod, autoCreateTable, " +
+				"tableType, primaryIndex, columnMapping, includeFields and excludeFields of a table may be set " +
+				"by the tables.<table>.* config values.",
+		},
+		config.KeyTableTemplate: {
+			Default: "",
+			Description: "A Go template rendering the name of the table a record is written to from the record, " +
+				"e.g. {{ index .Metadata \"opencdc.collection\" }}_raw. If it's set the firebolt.table metadata " +
+				"is ignored, and the record is written to the configured table if the rendered name is empty.",
+		},
+		config.KeyKeyColumns: {
+			Default: "",
+			Description: "Comma separated list of columns which values identify the rows to update or delete. " +
+				"By default all the fields of the record key are used.",
+		},
+		config.KeyWriteMode: {
+			Default: config.WriteModeInsert,
+			Description: "The way records with the create and snapshot operations are written, " +
+				"either insert or upsert, which replaces the existing rows with the same keys.",
+		},
+		config.KeyUpsertMethod: {
+			Default: config.UpsertMethodDeleteInsert,
+			Description: "The way records are upserted, either deleteInsert, which deletes the existing rows " +
+				"and inserts the new ones, or merge, which uses MERGE statements.",
+		},
+		config.KeySchemaEvolution: {
+			Default: config.SchemaEvolutionNone,
+			Description: "The way the payload fields missing in the table are handled, either none, " +
+				"which fails the record, addColumns, which adds the columns to the table, or ignore, " +
+				"which drops the fields.",
+		},
+		config.KeyColumnTypesTTL: {
+			Default: "5m",
+			Description: "The time after which the column types of a table are loaded again, " +
+				"zero means they're loaded again only after a schema error.",
+		},
+		config.KeyAutoCreateTable: {
+			Default: "false",
+			Description: "Whether to create the table from the first record if it doesn't exist. " +
+				"The column types are inferred from the record payload.",
+		},
+		config.KeyTableType: {
+			Default:     config.TableTypeFact,
+			Description: "The type of the created table, either fact or dimension.",
+		},
+		config.KeyPrimaryIndex: {
+			Default: "",
+			Description: "Comma separated list of primary index columns of the created table. " +
+				"By default the columns of the record key are used.",
+		},
+		config.KeyColumnMapping: {
+			Default: "",
+			Description: "Comma separated list of payload fields and the columns they're written to, " +
+				"e.g. userName:user_name,address.city:city. The nested fields are separated by dots.",
+		},
+		config.KeyIncludeFields: {
+			Default: "",
+			Description: "Comma separated list of payload fields written to the table, the nested fields are written " +
+				"to the columns named by their paths with underscores. By default all the top-level fields are written.",
+		},
+		config.KeyExcludeFields: {
 			Default:     "",
-			Required:    true,
-			Description: "The Firebolt database table name.",
+			Description: "Comma separated list of payload fields which aren't written to the table.",
+		},
+		config.KeyMaxInsertRows: {
+			Default:     "1000",
+			Description: "The maximum number of rows in a single INSERT statement.",
+		},
+		config.KeyMaxInsertBytes: {
+			Default:     "1048576",
+			Description: "The maximum size of a single INSERT statement in bytes.",
 		},
 	}
 }
@@ -97,23 +182,21 @@ func (d *Destination) Configure(_ context.Context, cfg map[string]string) error
 
 // Open makes sure everything is prepared to persists records.
 func (d *Destination) Open(ctx context.Context) error {
-	d.client = client.New(ctx, d.config.DB)
+	d.client = client.New(ctx, d.config.APIURL, d.config.DB)
 
 	err := d.client.Login(ctx, client.LoginParams{
-		Email:       d.config.Email,
-		Password:    d.config.Password,
-		AccountName: d.config.AccountName,
-		EngineName:  d.config.EngineName,
+		Email:        d.config.Email,
+		Password:     d.config.Password,
+		ClientID:     d.config.ClientID,
+		ClientSecret: d.config.ClientSecret,
+		AccountName:  d.config.AccountName,
+		EngineName:   d.config.EngineName,
+		EngineURL:    d.config.EngineURL,
 	})
 	if err != nil {
 		return fmt.Errorf("client login: %w", err)
 	}
 
-	d.writer, err = writer.NewWriter(d.client, d.config.Table)
-	if err != nil {
-		return fmt.Errorf("create writer: %w", err)
-	}
-
 	isEngineStarted, err := d.client.StartEngine(ctx)
 	if err != nil {
 		return fmt.Errorf("start engine: %w", err)
@@ -128,7 +211,42 @@ func (d *Destination) Open(ctx context.Context) error {
 		}
 	}
 
-	clTypes, err := d.client.GetColumnTypes(ctx, d.config.Table)
+	table, err := d.client.ResolveTable(ctx, d.config.Table)
+	if err != nil {
+		return fmt.Errorf("resolve table: %w", err)
+	}
+
+	w, err := writer.NewWriter(writer.Params{
+		Client:               d.client,
+		Table:                table,
+		KeyColumns:           d.config.KeyColumns,
+		MaxInsertRows:        d.config.MaxInsertRows,
+		MaxInsertBytes:       d.config.MaxInsertBytes,
+		Upsert:               d.config.WriteMode == config.WriteModeUpsert,
+		Merge:                d.config.UpsertMethod == config.UpsertMethodMerge,
+		AddColumns:           d.config.SchemaEvolution == config.SchemaEvolutionAddColumns,
+		IgnoreUnknownColumns: d.config.SchemaEvolution == config.SchemaEvolutionIgnore,
+		ColumnTypesTTL:       d.config.ColumnTypesTTL,
+		AutoCreateTable:      d.config.AutoCreateTable,
+		Dimension:            d.config.TableType == config.TableTypeDimension,
+		PrimaryIndex:         d.config.PrimaryIndex,
+		Tables:               d.tableParams(),
+		TableTemplate:        d.config.TableTemplate,
+		ColumnMapping:        columnMapping(d.config.ColumnMapping),
+	})
+	if err != nil {
+		return fmt.Errorf("create writer: %w", err)
+	}
+
+	d.writer = w
+
+	// the writer gets the column types when it creates the table or finds the existing one,
+	// the table may be created according to either the common or its own settings.
+	if w.AutoCreates(table) {
+		return nil
+	}
+
+	clTypes, err := d.client.GetColumnTypes(ctx, table)
 	if err != nil {
 		return fmt.Errorf("get column types:%w", err)
 	}
@@ -138,19 +256,36 @@ func (d *Destination) Open(ctx context.Context) error {
 	return nil
 }
 
-// Write writes a record into a Destination.
+// Write writes records into a Destination.
+// It returns the number of records written before the first failed one.
 func (d *Destination) Write(ctx context.Context, records []sdk.Record) (int, error) {
-	for i, record := range records {
-		// Firebolt doesn't support update and delete operations.
-		// Destination inserts record if operation value is snapshot or create
-		err := sdk.Util.Destination.Route(ctx, record,
-			d.writer.InsertRecord,
-			emptyHandle,
-			emptyHandle,
-			d.writer.InsertRecord,
-		)
-		if err != nil {
-			return i, fmt.Errorf("route %s: %w", record.Operation.String(), err)
+	for i := 0; i < len(records); {
+		switch records[i].Operation {
+		case sdk.OperationUpdate:
+			if err := d.writer.UpdateRecord(ctx, records[i]); err != nil {
+				return i, fmt.Errorf("update record: %w", err)
+			}
+
+			i++
+		case sdk.OperationDelete:
+			if err := d.writer.DeleteRecord(ctx, records[i]); err != nil {
+				return i, fmt.Errorf("delete record: %w", err)
+			}
+
+			i++
+		default:
+			// consecutive records to insert are written together.
+			end := i + 1
+			for end < len(records) && isInsert(records[end]) {
+				end++
+			}
+
+			n, err := d.writer.InsertRecords(ctx, records[i:end])
+			if err != nil {
+				return i + n, fmt.Errorf("insert records: %w", err)
+			}
+
+			i = end
 		}
 	}
 
@@ -166,7 +301,39 @@ func (d *Destination) Teardown(ctx context.Context) error {
 	return nil
 }
 
-// emptyHandle - default function to replacing update, delete functions for sdk.Route.
-func emptyHandle(_ context.Context, _ sdk.Record) error {
-	return nil
+// isInsert returns true if the record should be inserted.
+func isInsert(record sdk.Record) bool {
+	return record.Operation == sdk.OperationSnapshot || record.Operation == sdk.OperationCreate
+}
+
+// tableParams returns the writer params of the tables which have their own settings.
+func (d *Destination) tableParams() map[string]writer.TableParams {
+	if len(d.config.TableDestinations) == 0 {
+		return nil
+	}
+
+	params := make(map[string]writer.TableParams, len(d.config.TableDestinations))
+
+	for table, tableDestination := range d.config.TableDestinations {
+		params[table] = writer.TableParams{
+			KeyColumns:      tableDestination.KeyColumns,
+			Upsert:          tableDestination.WriteMode == config.WriteModeUpsert,
+			Merge:           tableDestination.UpsertMethod == config.UpsertMethodMerge,
+			AutoCreateTable: tableDestination.AutoCreateTable,
+			Dimension:       tableDestination.TableType == config.TableTypeDimension,
+			PrimaryIndex:    tableDestination.PrimaryIndex,
+			ColumnMapping:   columnMapping(tableDestination.ColumnMapping),
+		}
+	}
+
+	return params
+}
+
+// columnMapping returns the writer column mapping of the configured one.
+func columnMapping(mapping config.ColumnMapping) writer.ColumnMapping {
+	return writer.ColumnMapping{
+		Columns:       mapping.Columns,
+		IncludeFields: mapping.IncludeFields,
+		ExcludeFields: mapping.ExcludeFields,
+	}
 }
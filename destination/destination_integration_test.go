@@ -94,6 +94,7 @@ func TestDestination_Write_Success(t *testing.T) {
 			Operation: sdk.OperationCreate,
 		},
 		{Payload: sdk.Change{After: sdk.StructuredData(rc2)},
+			Key:       sdk.StructuredData{"id": rc2["id"]},
 			Operation: sdk.OperationUpdate,
 		},
 	},
@@ -107,7 +108,11 @@ func TestDestination_Write_Success(t *testing.T) {
 	is.NoErr(err)
 
 	// check data in firebolt
-	data, err := d.client.GetRows(ctx, cfg[config.KeyTable], []string{cfg[config.KeyOrderingColumns]}, nil, 2, 0)
+	data, err := d.client.GetRows(ctx, client.GetRowsParams{
+		Table:           cfg[config.KeyTable],
+		OrderingColumns: []string{cfg[config.KeyOrderingColumns]},
+		Limit:           2,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -204,13 +209,14 @@ func prepareConfig() (map[string]string, error) {
 }
 
 func prepareTable(ctx context.Context, cfg map[string]string) error {
-	cl := client.New(ctx, cfg[config.KeyDB])
+	cl := client.New(ctx, cfg[config.KeyAPIURL], cfg[config.KeyDB])
 
 	err := cl.Login(ctx, client.LoginParams{
 		Email:       cfg[config.KeyEmail],
 		Password:    cfg[config.KeyPassword],
 		AccountName: cfg[config.KeyAccountName],
 		EngineName:  cfg[config.KeyEngineName],
+		EngineURL:   cfg[config.KeyEngineURL],
 	})
 	if err != nil {
 		return fmt.Errorf("client login: %w", err)
@@ -226,13 +232,14 @@ func prepareTable(ctx context.Context, cfg map[string]string) error {
 }
 
 func clearData(ctx context.Context, cfg map[string]string) error {
-	cl := client.New(ctx, cfg[config.KeyDB])
+	cl := client.New(ctx, cfg[config.KeyAPIURL], cfg[config.KeyDB])
 
 	err := cl.Login(ctx, client.LoginParams{
 		Email:       cfg[config.KeyEmail],
 		Password:    cfg[config.KeyPassword],
 		AccountName: cfg[config.KeyAccountName],
 		EngineName:  cfg[config.KeyEngineName],
+		EngineURL:   cfg[config.KeyEngineURL],
 	})
 	if err != nil {
 		return fmt.Errorf("client login: %w", err)
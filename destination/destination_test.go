@@ -17,11 +17,15 @@ package destination
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
 	"testing"
 
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"go.uber.org/mock/gomock"
 
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+	"github.com/conduitio-labs/conduit-connector-firebolt/client/fireboltest"
 	"github.com/conduitio-labs/conduit-connector-firebolt/config"
 	"github.com/conduitio-labs/conduit-connector-firebolt/destination/mock"
 	"github.com/conduitio-labs/conduit-connector-firebolt/destination/writer"
@@ -123,7 +127,7 @@ func TestDestination_Write(t *testing.T) {
 		}
 
 		w := mock.NewMockWriter(ctrl)
-		w.EXPECT().InsertRecord(ctx, record).Return(nil)
+		w.EXPECT().InsertRecords(ctx, []sdk.Record{record}).Return(1, nil)
 
 		d := Destination{
 			writer: w,
@@ -151,7 +155,7 @@ func TestDestination_Write(t *testing.T) {
 		}
 
 		w := mock.NewMockWriter(ctrl)
-		w.EXPECT().InsertRecord(ctx, record).Return(writer.ErrEmptyPayload)
+		w.EXPECT().InsertRecords(ctx, []sdk.Record{record}).Return(0, writer.ErrEmptyPayload)
 
 		d := Destination{
 			writer: w,
@@ -162,6 +166,106 @@ func TestDestination_Write(t *testing.T) {
 			t.Errorf("want error")
 		}
 	})
+
+	t.Run("partially_failed_write", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ctx := context.Background()
+
+		records := make([]sdk.Record, 5)
+		for i := range records {
+			records[i] = sdk.Record{
+				Position:  sdk.Position(fmt.Sprintf("%d.0", i)),
+				Operation: sdk.OperationCreate,
+				Payload:   sdk.Change{After: sdk.StructuredData{"id": i}},
+			}
+		}
+
+		// an update record splits the records to insert.
+		records[1].Operation = sdk.OperationUpdate
+
+		w := mock.NewMockWriter(ctrl)
+		w.EXPECT().InsertRecords(ctx, records[:1]).Return(1, nil)
+		w.EXPECT().UpdateRecord(ctx, records[1]).Return(nil)
+		w.EXPECT().InsertRecords(ctx, records[2:]).Return(1, writer.ErrEmptyPayload)
+
+		d := Destination{
+			writer: w,
+		}
+
+		n, err := d.Write(ctx, records)
+		if !errors.Is(err, writer.ErrEmptyPayload) {
+			t.Errorf("want error: %v, got error: %v", writer.ErrEmptyPayload, err)
+		}
+
+		if n != 3 {
+			t.Errorf("written = %d, want 3", n)
+		}
+	})
+}
+
+func TestDestination_Write_UpdateDelete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ctx := context.Background()
+
+		update := sdk.Record{
+			Position:  sdk.Position("1.0"),
+			Key:       sdk.StructuredData{"id": 1},
+			Operation: sdk.OperationUpdate,
+			Payload:   sdk.Change{After: sdk.StructuredData{"id": 1, "name": "one"}},
+		}
+
+		del := sdk.Record{
+			Position:  sdk.Position("2.0"),
+			Key:       sdk.StructuredData{"id": 2},
+			Operation: sdk.OperationDelete,
+		}
+
+		w := mock.NewMockWriter(ctrl)
+		gomock.InOrder(
+			w.EXPECT().UpdateRecord(ctx, update).Return(nil),
+			w.EXPECT().DeleteRecord(ctx, del).Return(nil),
+		)
+
+		d := Destination{
+			writer: w,
+		}
+
+		n, err := d.Write(ctx, []sdk.Record{update, del})
+		if err != nil {
+			t.Errorf("write error = \"%s\"", err.Error())
+		}
+
+		if n != 2 {
+			t.Errorf("written = %d, want 2", n)
+		}
+	})
+
+	t.Run("failed_delete", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ctx := context.Background()
+
+		del := sdk.Record{
+			Position:  sdk.Position("1.0"),
+			Operation: sdk.OperationDelete,
+		}
+
+		w := mock.NewMockWriter(ctrl)
+		w.EXPECT().DeleteRecord(ctx, del).Return(writer.ErrEmptyKey)
+
+		d := Destination{
+			writer: w,
+		}
+
+		n, err := d.Write(ctx, []sdk.Record{del})
+		if !errors.Is(err, writer.ErrEmptyKey) {
+			t.Errorf("want error: %v, got error: %v", writer.ErrEmptyKey, err)
+		}
+
+		if n != 0 {
+			t.Errorf("written = %d, want 0", n)
+		}
+	})
 }
 
 func TestDestination_Teardown(t *testing.T) {
@@ -198,3 +302,172 @@ func TestDestination_Teardown(t *testing.T) {
 		}
 	})
 }
+
+func TestDestination_Open_TableSettings(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	// the configured table doesn't exist, it's created according to its own settings.
+	d := Destination{}
+
+	err := d.Configure(ctx, map[string]string{
+		config.KeyEmail:                fireboltest.Email,
+		config.KeyPassword:             fireboltest.Password,
+		config.KeyAccountName:          fireboltest.AccountName,
+		config.KeyEngineName:           fireboltest.EngineName,
+		config.KeyDB:                   fireboltest.DB,
+		config.KeyAPIURL:               srv.URL,
+		config.KeyTable:                "users",
+		"tables.users.autoCreateTable": "true",
+		"tables.users.tableType":       config.TableTypeDimension,
+	})
+	if err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+
+	if err = d.Open(ctx); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() {
+		if err = d.Teardown(ctx); err != nil {
+			t.Errorf("teardown: %v", err)
+		}
+	}()
+
+	_, err = d.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Key:       sdk.StructuredData{"id": 1},
+		Payload:   sdk.Change{After: sdk.StructuredData{"id": 1, "name": "one"}},
+	}})
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cl := client.New(ctx, srv.URL, fireboltest.DB)
+	defer cl.Close(ctx)
+
+	err = cl.Login(ctx, client.LoginParams{
+		Email:       fireboltest.Email,
+		Password:    fireboltest.Password,
+		AccountName: fireboltest.AccountName,
+		EngineName:  fireboltest.EngineName,
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	rows, err := cl.GetRows(ctx, client.GetRowsParams{Table: "users", OrderingColumns: []string{"id"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	want := []map[string]any{{"id": int64(1), "name": "one"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+}
+
+func TestDestination_MixedCaseNames(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	// the names created with quotes keep their case, the other ones are in lower case.
+	for _, query := range []string{
+		`CREATE DIMENSION TABLE "OrderItems" ("UserId" BIGINT, "Qty" INT, note TEXT)`,
+		"CREATE DIMENSION TABLE audit (event TEXT)",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := Destination{}
+
+	err := d.Configure(ctx, map[string]string{
+		config.KeyEmail:       fireboltest.Email,
+		config.KeyPassword:    fireboltest.Password,
+		config.KeyAccountName: fireboltest.AccountName,
+		config.KeyEngineName:  fireboltest.EngineName,
+		config.KeyDB:          fireboltest.DB,
+		config.KeyAPIURL:      srv.URL,
+		config.KeyTable:       "OrderItems",
+		config.KeyKeyColumns:  "UserId",
+	})
+	if err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+
+	if err = d.Open(ctx); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	defer func() {
+		if err = d.Teardown(ctx); err != nil {
+			t.Errorf("teardown: %v", err)
+		}
+	}()
+
+	_, err = d.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.StructuredData{"UserId": 1, "Qty": 2, "Note": "first"}},
+	}, {
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.StructuredData{"UserId": 2, "Qty": 1}},
+	}, {
+		Operation: sdk.OperationUpdate,
+		Key:       sdk.StructuredData{"UserId": 1},
+		Payload:   sdk.Change{After: sdk.StructuredData{"Qty": 5}},
+	}, {
+		Operation: sdk.OperationDelete,
+		Key:       sdk.StructuredData{"UserId": 2},
+	}, {
+		Operation: sdk.OperationCreate,
+		Metadata:  map[string]string{"firebolt.table": "Audit"},
+		Payload:   sdk.Change{After: sdk.StructuredData{"Event": "created"}},
+	}})
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cl := client.New(ctx, srv.URL, fireboltest.DB)
+	defer cl.Close(ctx)
+
+	err = cl.Login(ctx, client.LoginParams{
+		Email:       fireboltest.Email,
+		Password:    fireboltest.Password,
+		AccountName: fireboltest.AccountName,
+		EngineName:  fireboltest.EngineName,
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	rows, err := cl.GetRows(ctx, client.GetRowsParams{
+		Table:           "OrderItems",
+		OrderingColumns: []string{"UserId"},
+		Limit:           10,
+	})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	want := []map[string]any{{"UserId": int64(1), "Qty": int64(5), "note": "first"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+
+	rows, err = cl.GetRows(ctx, client.GetRowsParams{Table: "audit", OrderingColumns: []string{"event"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	want = []map[string]any{{"event": "created"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("audit rows = %v, want %v", rows, want)
+	}
+}
@@ -55,18 +55,33 @@ func (mr *MockWriterMockRecorder) Close(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockWriter)(nil).Close), ctx)
 }
 
-// InsertRecord mocks base method.
-func (m *MockWriter) InsertRecord(ctx context.Context, record sdk.Record) error {
+// DeleteRecord mocks base method.
+func (m *MockWriter) DeleteRecord(ctx context.Context, record sdk.Record) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertRecord", ctx, record)
+	ret := m.ctrl.Call(m, "DeleteRecord", ctx, record)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// InsertRecord indicates an expected call of InsertRecord.
-func (mr *MockWriterMockRecorder) InsertRecord(ctx, record any) *gomock.Call {
+// DeleteRecord indicates an expected call of DeleteRecord.
+func (mr *MockWriterMockRecorder) DeleteRecord(ctx, record any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertRecord", reflect.TypeOf((*MockWriter)(nil).InsertRecord), ctx, record)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecord", reflect.TypeOf((*MockWriter)(nil).DeleteRecord), ctx, record)
+}
+
+// InsertRecords mocks base method.
+func (m *MockWriter) InsertRecords(ctx context.Context, records []sdk.Record) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertRecords", ctx, records)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertRecords indicates an expected call of InsertRecords.
+func (mr *MockWriterMockRecorder) InsertRecords(ctx, records any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertRecords", reflect.TypeOf((*MockWriter)(nil).InsertRecords), ctx, records)
 }
 
 // SetColumnTypes mocks base method.
@@ -80,3 +95,17 @@ func (mr *MockWriterMockRecorder) SetColumnTypes(cl any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetColumnTypes", reflect.TypeOf((*MockWriter)(nil).SetColumnTypes), cl)
 }
+
+// UpdateRecord mocks base method.
+func (m *MockWriter) UpdateRecord(ctx context.Context, record sdk.Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecord", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecord indicates an expected call of UpdateRecord.
+func (mr *MockWriterMockRecorder) UpdateRecord(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecord", reflect.TypeOf((*MockWriter)(nil).UpdateRecord), ctx, record)
+}
@@ -0,0 +1,211 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+)
+
+// batchRow is a row of a record to insert.
+type batchRow struct {
+	table   string
+	columns []string
+	values  []any
+	// key is set only in the upsert mode.
+	key sdk.StructuredData
+	// keyID identifies the key in a batch.
+	keyID string
+}
+
+// insertBatch holds the rows of consecutive records which have the same table and columns,
+// and in the upsert mode the same key columns and different keys.
+type insertBatch struct {
+	table      string
+	columns    []string
+	keyColumns []string
+	rows       [][]any
+	keys       []map[string]any
+	keyIDs     map[string]struct{}
+}
+
+// newInsertBatch creates a batch for the rows similar to the row.
+func newInsertBatch(row batchRow) *insertBatch {
+	return &insertBatch{
+		table:      row.table,
+		columns:    row.columns,
+		keyColumns: sortedKeyColumns(row.key),
+		keyIDs:     make(map[string]struct{}),
+	}
+}
+
+// accepts checks if the row can be written together with the rows of the batch.
+func (b *insertBatch) accepts(row batchRow) bool {
+	if b == nil || row.table != b.table || !slices.Equal(row.columns, b.columns) {
+		return false
+	}
+
+	if row.key == nil {
+		return true
+	}
+
+	// the rows with the same key can't be upserted together.
+	_, ok := b.keyIDs[row.keyID]
+
+	return !ok && slices.Equal(sortedKeyColumns(row.key), b.keyColumns)
+}
+
+// add adds the row to the batch.
+func (b *insertBatch) add(row batchRow) {
+	b.rows = append(b.rows, row.values)
+
+	if row.key != nil {
+		b.keys = append(b.keys, row.key)
+		b.keyIDs[row.keyID] = struct{}{}
+	}
+}
+
+// prepareBatchRow returns the row of the record to insert, with the key of the record in the upsert mode.
+func (w *Writer) prepareBatchRow(ctx context.Context, record sdk.Record) (batchRow, error) {
+	table, columns, values, err := w.prepareRow(ctx, record)
+	if err != nil {
+		return batchRow{}, err
+	}
+
+	row := batchRow{table: table, columns: columns, values: values}
+
+	if !w.settingsOf(table).upsert {
+		return row, nil
+	}
+
+	if row.key, err = w.getKey(ctx, table, record); err != nil {
+		return batchRow{}, fmt.Errorf("get key: %w", err)
+	}
+
+	keyID, err := json.Marshal(row.key)
+	if err != nil {
+		return batchRow{}, fmt.Errorf("marshal key: %w", err)
+	}
+
+	row.keyID = string(keyID)
+
+	return row, nil
+}
+
+// writeBatch inserts or upserts the rows of the batch and returns the number of written rows.
+func (w *Writer) writeBatch(ctx context.Context, batch *insertBatch) (int, error) {
+	if batch == nil || len(batch.rows) == 0 {
+		return 0, nil
+	}
+
+	written, err := w.writeRows(ctx, batch)
+	if err != nil {
+		w.invalidateOnSchemaError(batch.table, err)
+	}
+
+	return written, err
+}
+
+// writeRows inserts or upserts the rows of the batch and returns the number of written rows.
+func (w *Writer) writeRows(ctx context.Context, batch *insertBatch) (int, error) {
+	if !w.settingsOf(batch.table).upsert {
+		inserted, err := w.client.InsertRows(ctx, client.InsertRowsParams{
+			Table:    batch.table,
+			Columns:  batch.columns,
+			Rows:     batch.rows,
+			MaxRows:  w.maxInsertRows,
+			MaxBytes: w.maxInsertBytes,
+		})
+		if err != nil {
+			return inserted, fmt.Errorf("insert rows: %w", err)
+		}
+
+		return inserted, nil
+	}
+
+	chunkSize := len(batch.rows)
+	if w.maxInsertRows > 0 {
+		chunkSize = w.maxInsertRows
+	}
+
+	var written int
+
+	// the rows are upserted in chunks, so the number of written rows is known if a chunk fails.
+	for start := 0; start < len(batch.rows); start += chunkSize {
+		n, err := w.upsertRows(ctx, batch, start, min(start+chunkSize, len(batch.rows)))
+		written += n
+
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// upsertRows replaces the existing rows with the same keys with the rows of the batch from start to end,
+// and returns the number of written rows.
+func (w *Writer) upsertRows(ctx context.Context, batch *insertBatch, start, end int) (int, error) {
+	rows := batch.rows[start:end]
+
+	if w.settingsOf(batch.table).merge {
+		err := w.client.MergeRows(ctx, client.MergeRowsParams{
+			Table:      batch.table,
+			Columns:    batch.columns,
+			KeyColumns: batch.keyColumns,
+			Rows:       rows,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("merge rows: %w", err)
+		}
+
+		return len(rows), nil
+	}
+
+	if err := w.client.DeleteRows(ctx, batch.table, batch.keys[start:end]); err != nil {
+		return 0, fmt.Errorf("delete rows: %w", err)
+	}
+
+	inserted, err := w.client.InsertRows(ctx, client.InsertRowsParams{
+		Table:    batch.table,
+		Columns:  batch.columns,
+		Rows:     rows,
+		MaxRows:  w.maxInsertRows,
+		MaxBytes: w.maxInsertBytes,
+	})
+	if err != nil {
+		return inserted, fmt.Errorf("insert rows: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// sortedKeyColumns returns the sorted columns of the key.
+func sortedKeyColumns(key sdk.StructuredData) []string {
+	columns := make([]string, 0, len(key))
+	for col := range key {
+		columns = append(columns, col)
+	}
+
+	slices.Sort(columns)
+
+	return columns
+}
@@ -0,0 +1,92 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// columnTypesCache holds the column types of the tables the records are written to.
+type columnTypesCache struct {
+	// ttl is a time after which the column types of a table are loaded again, zero means they don't expire.
+	ttl     time.Duration
+	entries map[string]columnTypesEntry
+	// now returns the current time, it's replaced in tests.
+	now func() time.Time
+}
+
+// columnTypesEntry is the column types of a table.
+type columnTypesEntry struct {
+	columnTypes map[string]string
+	loadedAt    time.Time
+}
+
+// newColumnTypesCache creates a cache with the ttl.
+func newColumnTypesCache(ttl time.Duration) *columnTypesCache {
+	return &columnTypesCache{
+		ttl:     ttl,
+		entries: make(map[string]columnTypesEntry),
+		now:     time.Now,
+	}
+}
+
+// get returns the column types of the table, if they're loaded and not expired.
+func (c *columnTypesCache) get(table string) (map[string]string, bool) {
+	entry, ok := c.entries[table]
+	if !ok {
+		return nil, false
+	}
+
+	if c.ttl > 0 && c.now().Sub(entry.loadedAt) >= c.ttl {
+		delete(c.entries, table)
+
+		return nil, false
+	}
+
+	return entry.columnTypes, true
+}
+
+// set stores the column types of the table.
+func (c *columnTypesCache) set(table string, columnTypes map[string]string) {
+	c.entries[table] = columnTypesEntry{columnTypes: columnTypes, loadedAt: c.now()}
+}
+
+// invalidate removes the column types of the table, so they're loaded again.
+func (c *columnTypesCache) invalidate(table string) {
+	delete(c.entries, table)
+}
+
+// getColumnTypes returns the column types of the table, they're loaded with DESCRIBE if they aren't cached.
+func (w *Writer) getColumnTypes(ctx context.Context, table string) (map[string]string, error) {
+	if columnTypes, ok := w.columnTypes.get(table); ok {
+		return columnTypes, nil
+	}
+
+	return w.loadColumnTypes(ctx, table)
+}
+
+// loadColumnTypes loads the column types of the table with DESCRIBE and caches them.
+func (w *Writer) loadColumnTypes(ctx context.Context, table string) (map[string]string, error) {
+	columnTypes, err := w.client.GetColumnTypes(ctx, table)
+	if err != nil {
+		return nil, fmt.Errorf("get column types of %q: %w", table, err)
+	}
+
+	w.columnTypes.set(table, columnTypes)
+
+	return columnTypes, nil
+}
@@ -0,0 +1,84 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestColumnTypesCache(t *testing.T) {
+	columnTypes := map[string]string{"id": "int"}
+
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		// elapsed is a time passed after the column types are set.
+		elapsed    time.Duration
+		invalidate bool
+		want       map[string]string
+		wantOK     bool
+	}{
+		{
+			name:    "not expired",
+			ttl:     time.Minute,
+			elapsed: 59 * time.Second,
+			want:    columnTypes,
+			wantOK:  true,
+		},
+		{
+			name:    "expired",
+			ttl:     time.Minute,
+			elapsed: time.Minute,
+		},
+		{
+			name:    "zero ttl doesn't expire",
+			elapsed: 24 * time.Hour,
+			want:    columnTypes,
+			wantOK:  true,
+		},
+		{
+			name:       "invalidated",
+			invalidate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+
+			cache := newColumnTypesCache(tt.ttl)
+			cache.now = func() time.Time { return now }
+
+			cache.set("users", columnTypes)
+
+			if tt.invalidate {
+				cache.invalidate("users")
+			}
+
+			now = now.Add(tt.elapsed)
+
+			got, ok := cache.get("users")
+			if ok != tt.wantOK {
+				t.Fatalf("get ok = %t, want %t", ok, tt.wantOK)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("get = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -19,7 +19,14 @@ import "errors"
 // ErrEmptyPayload occurs when there's no payload to insert.
 var (
 	ErrEmptyPayload                  = errors.New("payload is empty")
+	ErrEmptyKey                      = errors.New("key is empty")
+	ErrNoKeyColumn                   = errors.New("key column value doesn't exist")
 	ErrInvalidTimeLayout             = errors.New("invalid time layout")
 	ErrInvalidTypeForDateColumn      = errors.New("invalid type for date column")
 	ErrInvalidTypeForTimestampColumn = errors.New("invalid type for timestamp column")
+	ErrInvalidValueForIntegerColumn  = errors.New("invalid value for integer column")
+	ErrInvalidTypeForArrayColumn     = errors.New("invalid type for array column")
+	ErrTrailingData                  = errors.New("invalid data after top-level value")
+	ErrDuplicateColumn               = errors.New("several fields are written to the column")
+	ErrEmptyTableName                = errors.New("table template rendered an empty table name")
 )
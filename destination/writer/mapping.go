@@ -0,0 +1,163 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// pathSeparator separates the names of the nested fields in the field paths, e.g. address.city.
+const pathSeparator = "."
+
+// ColumnMapping is the way the payload fields are written to the columns.
+// The fields are referred by paths of their names in the payload, the names of the nested fields
+// are separated by dots, e.g. address.city.
+type ColumnMapping struct {
+	// Columns - the names of the columns by the paths of the fields written to them. The mapped fields are written
+	// even if they're not included, the nested fields are written to the columns named by their paths with underscores
+	// instead of dots by default.
+	Columns map[string]string
+	// IncludeFields - the paths of the fields written to the table, all the top-level fields are written if it's empty.
+	IncludeFields []string
+	// ExcludeFields - the paths of the fields which aren't written to the table.
+	// The excluded nested fields are removed from the objects written to the columns as a whole.
+	ExcludeFields []string
+}
+
+// isZero reports whether the mapping doesn't change the payload.
+func (m ColumnMapping) isZero() bool {
+	return len(m.Columns) == 0 && len(m.IncludeFields) == 0 && len(m.ExcludeFields) == 0
+}
+
+// apply returns the values of the payload fields by the names of the columns they're written to.
+// The payload is the decoded JSON data, the excluded nested fields are removed from it.
+func (m ColumnMapping) apply(payload map[string]any) (map[string]any, error) {
+	if m.isZero() {
+		return payload, nil
+	}
+
+	for _, path := range m.ExcludeFields {
+		if strings.Contains(path, pathSeparator) {
+			deleteField(payload, path)
+		}
+	}
+
+	paths := slices.Clone(m.IncludeFields)
+	if len(paths) == 0 {
+		paths = slices.Sorted(maps.Keys(payload))
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(m.Columns)) {
+		if !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+
+	fields := make(map[string]any, len(paths))
+
+	for _, path := range paths {
+		if slices.Contains(m.ExcludeFields, path) {
+			continue
+		}
+
+		value, ok := lookupField(payload, path)
+		if !ok {
+			continue
+		}
+
+		column := m.column(path)
+		if _, ok = fields[column]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateColumn, column)
+		}
+
+		fields[column] = value
+	}
+
+	return fields, nil
+}
+
+// applyToKey renames the fields of the key which are mapped to the columns. The other fields are kept,
+// as all the fields of the key identify the rows.
+func (m ColumnMapping) applyToKey(key map[string]any) map[string]any {
+	if len(m.Columns) == 0 {
+		return key
+	}
+
+	fields := make(map[string]any, len(key))
+
+	for field, value := range key {
+		if _, ok := m.Columns[field]; !ok {
+			fields[field] = value
+		}
+	}
+
+	for path, column := range m.Columns {
+		if value, ok := lookupField(key, path); ok {
+			fields[column] = value
+		}
+	}
+
+	return fields
+}
+
+// column returns the name of the column the field is written to.
+func (m ColumnMapping) column(path string) string {
+	if column, ok := m.Columns[path]; ok {
+		return column
+	}
+
+	return strings.ReplaceAll(path, pathSeparator, "_")
+}
+
+// lookupField returns the value of the field by its path.
+// A field which name contains dots is found by its name before the nested fields.
+func lookupField(fields map[string]any, path string) (any, bool) {
+	if value, ok := fields[path]; ok {
+		return value, true
+	}
+
+	name, rest, ok := strings.Cut(path, pathSeparator)
+	if !ok {
+		return nil, false
+	}
+
+	nested, ok := fields[name].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+
+	return lookupField(nested, rest)
+}
+
+// deleteField removes the field by its path.
+func deleteField(fields map[string]any, path string) {
+	if _, ok := fields[path]; ok {
+		delete(fields, path)
+
+		return
+	}
+
+	name, rest, ok := strings.Cut(path, pathSeparator)
+	if !ok {
+		return
+	}
+
+	if nested, ok := fields[name].(map[string]any); ok {
+		deleteField(nested, rest)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestColumnMapping_apply(t *testing.T) {
+	payload := `{"id":1,"userName":"jo","password":"secret","address":{"city":"Kyiv","zip":"01001"},"a.b":2}`
+
+	tests := []struct {
+		name    string
+		mapping ColumnMapping
+		want    map[string]any
+		wantErr error
+	}{
+		{
+			name: "no mapping",
+			want: map[string]any{
+				"id": json.Number("1"), "userName": "jo", "password": "secret",
+				"address": map[string]any{"city": "Kyiv", "zip": "01001"}, "a.b": json.Number("2"),
+			},
+		},
+		{
+			name: "renamed and excluded fields",
+			mapping: ColumnMapping{
+				Columns:       map[string]string{"userName": "User_Name", "address.city": "city"},
+				ExcludeFields: []string{"password", "address.zip", "a.b"},
+			},
+			want: map[string]any{
+				"id": json.Number("1"), "User_Name": "jo", "city": "Kyiv", "address": map[string]any{"city": "Kyiv"},
+			},
+		},
+		{
+			name: "included nested fields",
+			mapping: ColumnMapping{
+				Columns:       map[string]string{"userName": "name"},
+				IncludeFields: []string{"id", "address.city", "address.country", "a.b"},
+			},
+			want: map[string]any{
+				"id": json.Number("1"), "address_city": "Kyiv", "a_b": json.Number("2"), "name": "jo",
+			},
+		},
+		{
+			name: "nested field of an excluded object",
+			mapping: ColumnMapping{
+				Columns:       map[string]string{"address.zip": "zip"},
+				IncludeFields: []string{"id", "address"},
+				ExcludeFields: []string{"address"},
+			},
+			want: map[string]any{"id": json.Number("1"), "zip": "01001"},
+		},
+		{
+			name: "several fields written to a column",
+			mapping: ColumnMapping{
+				Columns: map[string]string{"userName": "id"},
+			},
+			wantErr: ErrDuplicateColumn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := make(map[string]any)
+			if err := unmarshalJSON([]byte(payload), &fields); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := tt.mapping.apply(fields)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("apply error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("apply = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
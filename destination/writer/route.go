@@ -0,0 +1,98 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+)
+
+// compileTableTemplate returns the compiled table template. The template fails to execute with a record
+// which map misses a key the template reads as a field, e.g. .Payload.After.tenant, while the index function
+// and the other ways to read a missing key result in an empty value, so the rendered name may be empty.
+func compileTableTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("table").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse table template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// getTableName returns the name of the table the record is written to.
+// If the table template is set, it's the template rendered with the record, otherwise it's the record metadata
+// value for table or the configured table if the record has no such metadata.
+// It returns ErrEmptyTableName if the rendered name is empty, e.g. the template reads a missing metadata key.
+func (w *Writer) getTableName(record sdk.Record) (string, error) {
+	if w.tableTemplate == nil {
+		tableName, ok := record.Metadata[metadataTable]
+		if !ok {
+			return w.table, nil
+		}
+
+		return tableName, nil
+	}
+
+	var buf bytes.Buffer
+	if err := w.tableTemplate.Execute(&buf, record); err != nil {
+		return "", fmt.Errorf("render table template: %w", err)
+	}
+
+	tableName := strings.TrimSpace(buf.String())
+	if tableName == "" {
+		return "", fmt.Errorf("render table template: %w", ErrEmptyTableName)
+	}
+
+	// the rendered name is quoted in the queries, so it must be a valid identifier.
+	if _, err := client.QuoteIdentifier(tableName); err != nil {
+		return "", fmt.Errorf("render table template: %w", err)
+	}
+
+	return tableName, nil
+}
+
+// recordTable returns the name of the table in the database the record is written to.
+func (w *Writer) recordTable(ctx context.Context, record sdk.Record) (string, error) {
+	table, err := w.getTableName(record)
+	if err != nil {
+		return "", err
+	}
+
+	return w.resolveTable(ctx, table)
+}
+
+// resolveTable returns the name of the table in the database the same way as the client's ResolveTable method,
+// e.g. a table created without quotes for a name in mixed case. Each name is resolved once.
+func (w *Writer) resolveTable(ctx context.Context, table string) (string, error) {
+	if resolved, ok := w.resolvedTables[table]; ok {
+		return resolved, nil
+	}
+
+	resolved, err := w.client.ResolveTable(ctx, table)
+	if err != nil {
+		return "", fmt.Errorf("resolve table %q: %w", table, err)
+	}
+
+	w.resolvedTables[table] = resolved
+
+	return resolved, nil
+}
@@ -0,0 +1,168 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+)
+
+// evolveSchema handles the payload fields which columns are missing in the table.
+// Depending on the mode it adds the columns to the table, or drops the fields from the payload.
+// The columns are defined by the record schema in the metadata, if it has them.
+func (w *Writer) evolveSchema(
+	ctx context.Context,
+	table string,
+	payload sdk.StructuredData,
+	metadata sdk.Metadata,
+) (sdk.StructuredData, error) {
+	if !w.addColumns && !w.ignoreUnknownColumns {
+		return payload, nil
+	}
+
+	columnTypes, err := w.getColumnTypes(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+
+	unknown := unknownColumns(payload, columnTypes)
+	if len(unknown) == 0 {
+		return payload, nil
+	}
+
+	if w.ignoreUnknownColumns {
+		for _, col := range unknown {
+			// the warning is logged once for each column of each table.
+			key := tableColumn{table: table, column: col}
+			if _, ok := w.ignoredColumns[key]; !ok {
+				w.ignoredColumns[key] = struct{}{}
+
+				sdk.Logger(ctx).Warn().Str("table", table).Str("column", col).
+					Msg("the table doesn't have the column, the field is ignored")
+			}
+		}
+
+		filtered := make(sdk.StructuredData, len(payload))
+		for col, value := range payload {
+			if !slices.Contains(unknown, col) {
+				filtered[col] = value
+			}
+		}
+
+		return filtered, nil
+	}
+
+	schema, err := schemaColumns(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, col := range unknown {
+		// the added columns are nullable, as the existing rows don't have their values.
+		column := defineColumn(schema, col, payload[col])
+		column.Nullable = true
+
+		if addErr := w.client.AddColumn(ctx, table, column); addErr != nil {
+			// the cached column types may be outdated, e.g. the column is added by another writer,
+			// so the column is added again only if it's still missing in the table.
+			if columnTypes, err = w.loadColumnTypes(ctx, table); err != nil {
+				return nil, err
+			}
+
+			if _, ok := columnTypes[col]; ok {
+				continue
+			}
+
+			return nil, fmt.Errorf("add column %q: %w", col, addErr)
+		}
+
+		sdk.Logger(ctx).Info().Str("table", table).Str("column", col).Str("type", column.Type).
+			Msg("column added")
+	}
+
+	if _, err = w.loadColumnTypes(ctx, table); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// tableColumn identifies a column of a table.
+type tableColumn struct {
+	table  string
+	column string
+}
+
+// resolveColumn returns the name of the column the field is written to, the columns are looked up the same way
+// as the tables: it's the name if there's such a column, otherwise the name in lower case if there's such a column,
+// as the names of the columns created without quotes are in lower case, or the name as is.
+func resolveColumn[V any](columns map[string]V, name string) string {
+	if _, ok := columns[name]; ok {
+		return name
+	}
+
+	if lower := strings.ToLower(name); lower != name {
+		if _, ok := columns[lower]; ok {
+			return lower
+		}
+	}
+
+	return name
+}
+
+// resolveFields returns the fields by the names of the columns they're written to.
+func resolveFields(fields sdk.StructuredData, columnTypes map[string]string) (sdk.StructuredData, error) {
+	resolved := make(sdk.StructuredData, len(fields))
+
+	for name, value := range fields {
+		column := resolveColumn(columnTypes, name)
+		if _, ok := resolved[column]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateColumn, column)
+		}
+
+		resolved[column] = value
+	}
+
+	return resolved, nil
+}
+
+// unknownColumns returns the sorted payload fields which columns are missing in the column types.
+func unknownColumns(payload sdk.StructuredData, columnTypes map[string]string) []string {
+	var unknown []string
+
+	for col := range payload {
+		if _, ok := columnTypes[col]; !ok {
+			unknown = append(unknown, col)
+		}
+	}
+
+	slices.Sort(unknown)
+
+	return unknown
+}
+
+// invalidateOnSchemaError removes the cached column types of the table if the error is a schema error,
+// as the table may have been changed, so the column types are loaded again for the next records.
+func (w *Writer) invalidateOnSchemaError(table string, err error) {
+	if client.IsSchemaError(err) {
+		w.columnTypes.invalidate(table)
+	}
+}
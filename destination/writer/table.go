@@ -0,0 +1,283 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+)
+
+// Firebolt column types of the created tables.
+const (
+	columnTypeBoolean   = "BOOLEAN"
+	columnTypeInt       = "INT"
+	columnTypeBigInt    = "BIGINT"
+	columnTypeDouble    = "DOUBLE"
+	columnTypeNumeric   = "NUMERIC(38, 0)"
+	columnTypeText      = "TEXT"
+	columnTypeTimestamp = "TIMESTAMP"
+)
+
+// numericColumnTypes are the numeric column types from the narrowest to the widest one.
+var numericColumnTypes = []string{columnTypeInt, columnTypeBigInt, columnTypeNumeric, columnTypeDouble}
+
+// ensureTable creates the table of the record from the record, if the automatic table creation is enabled
+// for the table and the table doesn't exist, and sets the column types of the created table.
+// It does nothing after the first call for the table that succeeded.
+func (w *Writer) ensureTable(ctx context.Context, record sdk.Record) error {
+	table, err := w.recordTable(ctx, record)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := w.ensuredTables[table]; ok || !w.AutoCreates(table) {
+		return nil
+	}
+
+	exists, err := w.client.TableExists(ctx, table)
+	if err != nil {
+		return fmt.Errorf("check table exists: %w", err)
+	}
+
+	// the column types of the existing table are loaded with the first record.
+	if exists {
+		w.ensuredTables[table] = struct{}{}
+
+		return nil
+	}
+
+	params, err := w.inferTable(table, record)
+	if err != nil {
+		return fmt.Errorf("infer table: %w", err)
+	}
+
+	if err = w.client.CreateTable(ctx, params); err != nil {
+		return fmt.Errorf("create table: %w", err)
+	}
+
+	columnTypes := make(map[string]string, len(params.Columns))
+	for _, col := range params.Columns {
+		columnTypes[col.Name] = strings.ToLower(col.Type)
+	}
+
+	w.columnTypes.set(table, columnTypes)
+	w.ensuredTables[table] = struct{}{}
+
+	sdk.Logger(ctx).Info().Str("table", table).Msg("table created")
+
+	return nil
+}
+
+// AutoCreates reports whether the table is created if it doesn't exist. The common setting applies to
+// the configured table only, while the tables with their own settings are created according to them.
+func (w *Writer) AutoCreates(table string) bool {
+	if settings, ok := w.ownSettings(table); ok {
+		return settings.autoCreateTable
+	}
+
+	return w.settings.autoCreateTable && table == w.table
+}
+
+// inferTable returns the params to create the table with the columns of the record payload.
+// The primary index columns are the configured ones or, by default, the columns of the record key.
+func (w *Writer) inferTable(table string, record sdk.Record) (client.CreateTableParams, error) {
+	settings := w.settingsOf(table)
+
+	schema, err := schemaColumns(record.Metadata)
+	if err != nil {
+		return client.CreateTableParams{}, err
+	}
+
+	payload, err := w.structurizeData(table, record.Payload.After)
+	if err != nil {
+		return client.CreateTableParams{}, fmt.Errorf("structurize payload: %w", err)
+	}
+
+	if len(payload) == 0 {
+		return client.CreateTableParams{}, ErrEmptyPayload
+	}
+
+	// the configured columns are resolved to the payload fields the same way as to the columns of a table.
+	primaryIndex := make([]string, len(settings.primaryIndex))
+	for i, col := range settings.primaryIndex {
+		primaryIndex[i] = resolveColumn(payload, col)
+	}
+
+	if len(primaryIndex) == 0 {
+		key, er := w.structurizeKey(table, record.Key)
+		if er != nil {
+			return client.CreateTableParams{}, fmt.Errorf("structurize key: %w", er)
+		}
+
+		for col := range key {
+			if _, ok := payload[col]; ok {
+				primaryIndex = append(primaryIndex, col)
+			}
+		}
+
+		slices.Sort(primaryIndex)
+	}
+
+	columns, values := w.extractColumnsAndValues(payload)
+
+	params := client.CreateTableParams{
+		Table:        table,
+		Dimension:    settings.dimension,
+		Columns:      make([]client.ColumnDefinition, len(columns)),
+		PrimaryIndex: primaryIndex,
+	}
+
+	for i, col := range columns {
+		params.Columns[i] = defineColumn(schema, col, values[i])
+
+		// Firebolt doesn't allow nullable primary index columns.
+		if slices.Contains(primaryIndex, col) {
+			params.Columns[i].Nullable = false
+		}
+	}
+
+	return params, nil
+}
+
+// schemaColumns returns the columns of the record schema in the metadata by their names,
+// it's nil if the metadata has no schema.
+func schemaColumns(metadata sdk.Metadata) (map[string]client.Column, error) {
+	encoded, ok := metadata[metadataSchema]
+	if !ok {
+		return nil, nil
+	}
+
+	var schema struct {
+		Columns []client.Column `json:"columns"`
+	}
+
+	if err := json.Unmarshal([]byte(encoded), &schema); err != nil {
+		return nil, fmt.Errorf("unmarshal record schema: %w", err)
+	}
+
+	columns := make(map[string]client.Column, len(schema.Columns))
+	for _, col := range schema.Columns {
+		columns[col.Name] = col
+	}
+
+	return columns, nil
+}
+
+// defineColumn returns the definition of the column of the field. The type and the nullability are the ones
+// of the column of the record schema, if it has the column, otherwise the type is inferred from the value
+// and the column is nullable.
+func defineColumn(schema map[string]client.Column, name string, value any) client.ColumnDefinition {
+	if col, ok := schema[name]; ok {
+		if typ, ok := client.ColumnDefinitionType(col.Type); ok {
+			return client.ColumnDefinition{Name: name, Type: typ, Nullable: col.Nullable}
+		}
+	}
+
+	return client.ColumnDefinition{Name: name, Type: inferColumnType(value), Nullable: true}
+}
+
+// inferColumnType returns the Firebolt column type of the value.
+// Numbers without a fractional part are integers, or numerics if they're out of the BIGINT range,
+// strings in RFC3339 format are timestamps, slices are arrays, and the values of unknown types, including nil,
+// are stored as text.
+func inferColumnType(value any) string {
+	switch v := value.(type) {
+	case bool:
+		return columnTypeBoolean
+	case int8, int16, int32, uint8, uint16:
+		return columnTypeInt
+	case int, int64, uint, uint32, uint64:
+		return columnTypeBigInt
+	case float32:
+		return columnTypeDouble
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= math.MaxInt64 {
+			return columnTypeBigInt
+		}
+
+		return columnTypeDouble
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return columnTypeBigInt
+		}
+
+		// integers out of the BIGINT range are stored exactly as numerics.
+		if r, ok := new(big.Rat).SetString(v.String()); ok && r.IsInt() {
+			return columnTypeNumeric
+		}
+
+		return columnTypeDouble
+	case time.Time:
+		return columnTypeTimestamp
+	case string:
+		if _, err := time.Parse(time.RFC3339, v); err == nil {
+			return columnTypeTimestamp
+		}
+
+		return columnTypeText
+	default:
+		return inferArrayType(value)
+	}
+}
+
+// inferArrayType returns the array type of the slice, the elements are nullable and their type is inferred
+// from the non-nil elements, it's TEXT if there are none. The numbers of different types are stored as the widest
+// of them. The slices of the values of other different types, byte slices and the values that aren't slices
+// are stored as text.
+func inferArrayType(value any) string {
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() || rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array ||
+		rv.Type().Elem().Kind() == reflect.Uint8 {
+		return columnTypeText
+	}
+
+	var elemType string
+
+	for i := range rv.Len() {
+		elem := rv.Index(i).Interface()
+		if elem == nil {
+			continue
+		}
+
+		typ := inferColumnType(elem)
+		known, next := slices.Index(numericColumnTypes, elemType), slices.Index(numericColumnTypes, typ)
+
+		switch {
+		case elemType == "" || elemType == typ:
+			elemType = typ
+		case known >= 0 && next >= 0:
+			elemType = numericColumnTypes[max(known, next)]
+		default:
+			return columnTypeText
+		}
+	}
+
+	if elemType == "" {
+		elemType = columnTypeText
+	}
+
+	return "ARRAY(" + elemType + " NULL)"
+}
@@ -15,11 +15,18 @@
 package writer
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
 	"reflect"
+	"slices"
 	"strings"
+	"text/template"
 	"time"
 
 	sdk "github.com/conduitio/conduit-connector-sdk"
@@ -30,10 +37,21 @@ import (
 const (
 	// metadata related.
 	metadataTable = "firebolt.table"
+	// metadataSchema is the record schema encoded as JSON by the source.
+	metadataSchema = "firebolt.schema"
 
 	// column types.
 	typeTimestamp = "timestamp"
 	typeDate      = "date"
+	typeInt       = "int"
+	typeInteger   = "integer"
+	typeBigInt    = "bigint"
+	typeLong      = "long"
+	typeArray     = "array"
+
+	// value layouts of the date and timestamp columns, timestamps keep microseconds.
+	dateLayout      = "2006-01-02"
+	timestampLayout = "2006-01-02 15:04:05.999999"
 )
 
 var (
@@ -43,48 +61,281 @@ var (
 		time.RFC3339Nano, time.Kitchen, time.Stamp, time.StampMilli, time.StampMicro, time.StampNano}
 )
 
+// Params is incoming params for the NewWriter function.
+type Params struct {
+	Client *client.Client
+	Table  string
+	// KeyColumns - columns which values identify the rows to update or delete.
+	KeyColumns []string
+	// MaxInsertRows and MaxInsertBytes limit the number of rows and the size of a single INSERT statement.
+	MaxInsertRows  int
+	MaxInsertBytes int
+	// Upsert - records to insert replace the existing rows with the same keys.
+	Upsert bool
+	// Merge - records are upserted using MERGE statements instead of deleting and inserting rows.
+	Merge bool
+	// AddColumns - the columns of the payload fields missing in the table are added to the table.
+	AddColumns bool
+	// IgnoreUnknownColumns - the payload fields missing in the table are dropped.
+	IgnoreUnknownColumns bool
+	// AutoCreateTable - the table is created from the first record if it doesn't exist.
+	AutoCreateTable bool
+	// Dimension - the created table is a dimension table, otherwise it's a fact table.
+	Dimension bool
+	// PrimaryIndex - the primary index columns of the created table, by default the record key columns.
+	PrimaryIndex []string
+	// ColumnTypesTTL - the time after which the column types of a table are loaded again,
+	// zero means they're loaded again only after a schema error.
+	ColumnTypesTTL time.Duration
+	// Tables - the params of the tables by their names, which are used instead of the ones above.
+	Tables map[string]TableParams
+	// TableTemplate - a Go template rendering the name of the table a record is written to from the record,
+	// if it's set the metadata value for table is ignored.
+	TableTemplate string
+	// ColumnMapping - the way the payload fields are written to the columns.
+	ColumnMapping ColumnMapping
+}
+
+// TableParams is the params of writing the records to a table.
+type TableParams struct {
+	// KeyColumns - columns which values identify the rows to update or delete.
+	KeyColumns []string
+	// Upsert - records to insert replace the existing rows with the same keys.
+	Upsert bool
+	// Merge - records are upserted using MERGE statements instead of deleting and inserting rows.
+	Merge bool
+	// AutoCreateTable - the table is created from the first record if it doesn't exist.
+	AutoCreateTable bool
+	// Dimension - the created table is a dimension table, otherwise it's a fact table.
+	Dimension bool
+	// PrimaryIndex - the primary index columns of the created table, by default the record key columns.
+	PrimaryIndex []string
+	// ColumnMapping - the way the payload fields are written to the columns.
+	ColumnMapping ColumnMapping
+}
+
+// tableSettings holds the settings of writing the records to a table.
+type tableSettings struct {
+	keyColumns      []string
+	upsert          bool
+	merge           bool
+	autoCreateTable bool
+	dimension       bool
+	primaryIndex    []string
+	columnMapping   ColumnMapping
+}
+
 // Writer implements write logic for Firebolt destination.
 type Writer struct {
-	client      *client.Client
-	table       string
-	columnTypes map[string]string
+	client               *client.Client
+	table                string
+	columnTypes          *columnTypesCache
+	maxInsertRows        int
+	maxInsertBytes       int
+	addColumns           bool
+	ignoreUnknownColumns bool
+	// ignoredColumns holds the dropped fields, to warn about each of them once.
+	ignoredColumns map[tableColumn]struct{}
+	// settings are the settings of the tables which don't have their own ones in the tableSettings.
+	settings      tableSettings
+	tableSettings map[string]tableSettings
+	// ensuredTables holds the tables which are created or found by the automatic table creation.
+	ensuredTables map[string]struct{}
+	// resolvedTables holds the names of the tables in the database by the names the records are routed to.
+	resolvedTables map[string]string
+	tableTemplate  *template.Template
 }
 
 // NewWriter creates new instance of the Writer.
-func NewWriter(client *client.Client, table string) (*Writer, error) {
-	return &Writer{
-		client: client,
-		table:  table,
-	}, nil
+func NewWriter(params Params) (*Writer, error) {
+	w := &Writer{
+		client:               params.Client,
+		table:                params.Table,
+		columnTypes:          newColumnTypesCache(params.ColumnTypesTTL),
+		maxInsertRows:        params.MaxInsertRows,
+		maxInsertBytes:       params.MaxInsertBytes,
+		addColumns:           params.AddColumns,
+		ignoreUnknownColumns: params.IgnoreUnknownColumns,
+		ignoredColumns:       make(map[tableColumn]struct{}),
+		settings: newTableSettings(TableParams{
+			KeyColumns:      params.KeyColumns,
+			Upsert:          params.Upsert,
+			Merge:           params.Merge,
+			AutoCreateTable: params.AutoCreateTable,
+			Dimension:       params.Dimension,
+			PrimaryIndex:    params.PrimaryIndex,
+			ColumnMapping:   params.ColumnMapping,
+		}),
+		tableSettings:  make(map[string]tableSettings, len(params.Tables)),
+		ensuredTables:  make(map[string]struct{}),
+		resolvedTables: map[string]string{params.Table: params.Table},
+	}
+
+	for table, tableParams := range params.Tables {
+		w.tableSettings[table] = newTableSettings(tableParams)
+	}
+
+	if params.TableTemplate != "" {
+		var err error
+		if w.tableTemplate, err = compileTableTemplate(params.TableTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
+
+// newTableSettings returns the settings of a table.
+func newTableSettings(params TableParams) tableSettings {
+	return tableSettings{
+		keyColumns:      params.KeyColumns,
+		upsert:          params.Upsert,
+		merge:           params.Merge,
+		autoCreateTable: params.AutoCreateTable,
+		dimension:       params.Dimension,
+		primaryIndex:    params.PrimaryIndex,
+		columnMapping:   params.ColumnMapping,
+	}
+}
+
+// settingsOf returns the settings of the table, they're the common ones unless the table has its own settings.
+func (w *Writer) settingsOf(table string) tableSettings {
+	if settings, ok := w.ownSettings(table); ok {
+		return settings
+	}
+
+	return w.settings
+}
+
+// ownSettings returns the settings of the table if it has its own ones. The table is resolved from the configured
+// name the same way as the configured table, so the settings of a table created without quotes may be
+// configured under its name in any case.
+func (w *Writer) ownSettings(table string) (tableSettings, bool) {
+	if settings, ok := w.tableSettings[table]; ok {
+		return settings, true
+	}
+
+	for name, settings := range w.tableSettings {
+		if strings.ToLower(name) == table {
+			return settings, true
+		}
+	}
+
+	return tableSettings{}, false
 }
 
+// SetColumnTypes sets the column types of the configured table.
 func (w *Writer) SetColumnTypes(cl map[string]string) {
-	w.columnTypes = cl
+	w.columnTypes.set(w.table, cl)
+}
+
+// InsertRecords inserts records into a Destination, or upserts them in the upsert mode.
+// Consecutive records with the same table and columns are inserted with multi-row INSERT statements.
+// It returns the number of inserted records, the records are inserted in order,
+// so in case of an error the records that follow the inserted ones are not inserted.
+func (w *Writer) InsertRecords(ctx context.Context, records []sdk.Record) (int, error) {
+	var (
+		inserted int
+		batch    *insertBatch
+	)
+
+	for _, record := range records {
+		if err := w.ensureTable(ctx, record); err != nil {
+			n, er := w.writeBatch(ctx, batch)
+			if er != nil {
+				return inserted + n, er
+			}
+
+			return inserted + n, fmt.Errorf("ensure table: %w", err)
+		}
+
+		row, err := w.prepareBatchRow(ctx, record)
+		if err != nil {
+			n, er := w.writeBatch(ctx, batch)
+			if er != nil {
+				return inserted + n, er
+			}
+
+			return inserted + n, err
+		}
+
+		if !batch.accepts(row) {
+			n, er := w.writeBatch(ctx, batch)
+			inserted += n
+
+			if er != nil {
+				return inserted, er
+			}
+
+			batch = newInsertBatch(row)
+		}
+
+		batch.add(row)
+	}
+
+	n, err := w.writeBatch(ctx, batch)
+
+	return inserted + n, err
 }
 
-// InsertRecord inserts a record into a Destination.
-func (w *Writer) InsertRecord(ctx context.Context, record sdk.Record) error {
-	table := w.getTableName(record.Metadata)
+// UpdateRecord updates the rows matching the record key with the record payload.
+func (w *Writer) UpdateRecord(ctx context.Context, record sdk.Record) error {
+	if err := w.ensureTable(ctx, record); err != nil {
+		return fmt.Errorf("ensure table: %w", err)
+	}
 
-	payload, err := w.structurizeData(record.Payload.After)
+	table, columns, values, err := w.prepareRow(ctx, record)
 	if err != nil {
-		return fmt.Errorf("structurize payload: %w", err)
+		return err
 	}
 
-	// if payload is empty we don't need to insert anything
-	if payload == nil {
-		return ErrEmptyPayload
+	key, err := w.getKey(ctx, table, record)
+	if err != nil {
+		return fmt.Errorf("get key: %w", err)
 	}
 
-	payload, err = w.convertPayload(payload)
+	// the key columns are used to find the rows, so they aren't updated.
+	var (
+		setColumns []string
+		setValues  []any
+	)
+
+	for i, col := range columns {
+		if _, ok := key[col]; !ok {
+			setColumns = append(setColumns, col)
+			setValues = append(setValues, values[i])
+		}
+	}
+
+	if len(setColumns) == 0 {
+		return nil
+	}
+
+	if err = w.client.UpdateRow(ctx, table, key, setColumns, setValues); err != nil {
+		w.invalidateOnSchemaError(table, err)
+
+		return fmt.Errorf("update row: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRecord deletes the rows matching the record key.
+func (w *Writer) DeleteRecord(ctx context.Context, record sdk.Record) error {
+	table, err := w.recordTable(ctx, record)
 	if err != nil {
-		return fmt.Errorf("convert payload: %w", err)
+		return err
 	}
 
-	columns, values := w.extractColumnsAndValues(payload)
+	key, err := w.getKey(ctx, table, record)
+	if err != nil {
+		return fmt.Errorf("get key: %w", err)
+	}
+
+	if err = w.client.DeleteRow(ctx, table, key); err != nil {
+		w.invalidateOnSchemaError(table, err)
 
-	if err = w.client.InsertRow(ctx, table, columns, values); err != nil {
-		return fmt.Errorf("insert row: %w", err)
+		return fmt.Errorf("delete row: %w", err)
 	}
 
 	return nil
@@ -97,142 +348,392 @@ func (w *Writer) Close(ctx context.Context) error {
 	return nil
 }
 
-// getTableName returns either the records metadata value for table
-// or the default configured value for table.
-func (w *Writer) getTableName(metadata map[string]string) string {
-	tableName, ok := metadata[metadataTable]
-	if !ok {
-		return w.table
+// prepareRow returns the table, the columns and the values of the record payload.
+// The payload fields missing in the configured table are handled according to the schema evolution mode.
+func (w *Writer) prepareRow(ctx context.Context, record sdk.Record) (string, []string, []any, error) {
+	table, err := w.recordTable(ctx, record)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	payload, err := w.structurizeData(table, record.Payload.After)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("structurize payload: %w", err)
+	}
+
+	// if payload is empty we don't need to insert anything, e.g. none of the included fields is set.
+	if len(payload) == 0 {
+		return "", nil, nil, ErrEmptyPayload
+	}
+
+	columnTypes, err := w.getColumnTypes(ctx, table)
+	if err != nil {
+		return "", nil, nil, err
 	}
 
-	return strings.ToLower(tableName)
+	if payload, err = resolveFields(payload, columnTypes); err != nil {
+		return "", nil, nil, fmt.Errorf("resolve payload columns: %w", err)
+	}
+
+	payload, err = w.evolveSchema(ctx, table, payload, record.Metadata)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("evolve schema: %w", err)
+	}
+
+	// the column types are loaded again if the columns are added.
+	if columnTypes, err = w.getColumnTypes(ctx, table); err != nil {
+		return "", nil, nil, err
+	}
+
+	payload, err = w.convertPayload(payload, columnTypes)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("convert payload: %w", err)
+	}
+
+	columns, values := w.extractColumnsAndValues(payload)
+
+	return table, columns, values, nil
 }
 
-// structurizeData converts sdk.Data to sdk.StructuredData.
-func (w *Writer) structurizeData(data sdk.Data) (sdk.StructuredData, error) {
+// getKey returns the columns and the values identifying the rows of the record.
+// If the key columns are configured, only their values are used, the values missed in the record key
+// are taken from the record payload.
+// If the record key is raw data that isn't a JSON object, it's the value of the single key column.
+func (w *Writer) getKey(ctx context.Context, table string, record sdk.Record) (sdk.StructuredData, error) {
+	keyColumns := w.settingsOf(table).keyColumns
+
+	columnTypes, err := w.getColumnTypes(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := w.structurizeKey(table, record.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err = resolveFields(key, columnTypes); err != nil {
+		return nil, fmt.Errorf("resolve key columns: %w", err)
+	}
+
+	if len(keyColumns) > 0 {
+		payload, er := w.structurizeData(table, record.Payload.After)
+		if er != nil {
+			return nil, fmt.Errorf("structurize payload: %w", er)
+		}
+
+		if payload == nil {
+			if payload, er = w.structurizeData(table, record.Payload.Before); er != nil {
+				return nil, fmt.Errorf("structurize payload: %w", er)
+			}
+		}
+
+		if payload, er = resolveFields(payload, columnTypes); er != nil {
+			return nil, fmt.Errorf("resolve payload columns: %w", er)
+		}
+
+		columnsKey := make(sdk.StructuredData, len(keyColumns))
+
+		for _, col := range keyColumns {
+			col = resolveColumn(columnTypes, col)
+
+			value, ok := key[col]
+			if !ok {
+				if value, ok = payload[col]; !ok {
+					return nil, fmt.Errorf("%s: %w", col, ErrNoKeyColumn)
+				}
+			}
+
+			columnsKey[col] = value
+		}
+
+		key = columnsKey
+	}
+
+	if len(key) == 0 {
+		return nil, ErrEmptyKey
+	}
+
+	return w.convertPayload(key, columnTypes)
+}
+
+// structurizeKey converts the record key to sdk.StructuredData, a raw key is the value of the single key column.
+// The mapped fields of the key are renamed to their columns.
+func (w *Writer) structurizeKey(table string, key sdk.Data) (sdk.StructuredData, error) {
+	if key == nil || len(key.Bytes()) == 0 {
+		return nil, nil
+	}
+
+	settings := w.settingsOf(table)
+
+	fields, err := decodeData(key)
+	if err == nil {
+		return encodeObjects(settings.columnMapping.applyToKey(fields))
+	}
+
+	if len(settings.keyColumns) != 1 {
+		return nil, fmt.Errorf("key is not a JSON object and a single key column is not configured: %w", err)
+	}
+
+	// the raw key is a single value, e.g. 1 or "id".
+	var value any
+	if er := unmarshalJSON(key.Bytes(), &value); er != nil {
+		value = string(key.Bytes())
+	}
+
+	return sdk.StructuredData{settings.keyColumns[0]: value}, nil
+}
+
+// structurizeData converts sdk.Data to sdk.StructuredData, the fields are mapped to the columns of the table.
+func (w *Writer) structurizeData(table string, data sdk.Data) (sdk.StructuredData, error) {
+	fields, err := decodeData(data)
+	if err != nil || fields == nil {
+		return nil, err
+	}
+
+	if fields, err = w.settingsOf(table).columnMapping.apply(fields); err != nil {
+		return nil, fmt.Errorf("map columns: %w", err)
+	}
+
+	return encodeObjects(fields)
+}
+
+// decodeData decodes sdk.Data as a JSON object, it returns nil if the data is empty.
+func decodeData(data sdk.Data) (map[string]any, error) {
 	if data == nil || len(data.Bytes()) == 0 {
 		return nil, nil
 	}
 
-	structuredData := make(sdk.StructuredData)
-	if err := json.Unmarshal(data.Bytes(), &structuredData); err != nil {
+	fields := make(map[string]any)
+	if err := unmarshalJSON(data.Bytes(), &fields); err != nil {
 		return nil, fmt.Errorf("unmarshal data into structured data: %w", err)
 	}
 
-	// Firebolt API returns columns names as lower case, it is converts keys to lower case too.
-	structuredDataLower := make(sdk.StructuredData)
-	for key, value := range structuredData {
+	return fields, nil
+}
+
+// encodeObjects returns the fields with the nested objects converted to JSON strings.
+func encodeObjects(fields map[string]any) (sdk.StructuredData, error) {
+	structuredData := make(sdk.StructuredData, len(fields))
+	for key, value := range fields {
 		if parsedValue, ok := value.(map[string]any); ok {
 			jsonValue, err := json.Marshal(parsedValue)
 			if err != nil {
 				return nil, fmt.Errorf("marshal map into json: %w", err)
 			}
 
-			structuredDataLower[strings.ToLower(key)] = string(jsonValue)
+			structuredData[key] = string(jsonValue)
 
 			continue
 		}
 
-		structuredDataLower[strings.ToLower(key)] = value
+		structuredData[key] = value
 	}
 
-	return structuredDataLower, nil
+	return structuredData, nil
+}
+
+// unmarshalJSON parses the JSON data like json.Unmarshal, but decodes numbers as json.Number,
+// so the integers out of the float64 precision aren't corrupted.
+func unmarshalJSON(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return ErrTrailingData
+	}
+
+	return nil
 }
 
 // extractColumnsAndValues turns the payload into slices of
-// columns and values for upserting into Firebolt.
+// columns and values for upserting into Firebolt, the columns are sorted by name.
 func (w *Writer) extractColumnsAndValues(payload sdk.StructuredData) ([]string, []any) {
-	var (
-		columns []string
-		values  []any
-	)
-
-	for key, value := range payload {
+	columns := make([]string, 0, len(payload))
+	for key := range payload {
 		columns = append(columns, key)
-		values = append(values, value)
+	}
+
+	slices.Sort(columns)
+
+	values := make([]any, len(columns))
+	for i, col := range columns {
+		values[i] = payload[col]
 	}
 
 	return columns, values
 }
 
 // convertPayload converts a sdk.StructureData values to a proper database types.
-func (w *Writer) convertPayload(data sdk.StructuredData) (sdk.StructuredData, error) {
+func (w *Writer) convertPayload(data sdk.StructuredData, columnTypes map[string]string) (sdk.StructuredData, error) {
 	result := make(sdk.StructuredData, len(data))
 
 	for key, value := range data {
-		if value == nil {
-			result[key] = value
-
-			continue
+		converted, err := w.convertValue(value, columnTypes[key])
+		if err != nil {
+			return nil, fmt.Errorf("convert %q value: %w", key, err)
 		}
 
-		switch reflect.TypeOf(value).Kind() {
-		case reflect.Map, reflect.Slice:
-			bs, err := json.Marshal(value)
-			if err != nil {
-				return nil, fmt.Errorf("marshal: %w", err)
-			}
+		result[key] = converted
+	}
 
-			result[key] = string(bs)
+	return result, nil
+}
 
-			continue
+// convertValue converts the value to the database type of the column.
+// The values of array columns are converted element by element and written as array literals,
+// maps and slices written to other columns are converted to JSON strings.
+func (w *Writer) convertValue(value any, columnType string) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	if elemType, ok := arrayElementType(columnType); ok {
+		return w.convertArray(value, elemType)
+	}
+
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Map, reflect.Slice:
+		bs, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("marshal: %w", err)
 		}
 
-		switch w.columnTypes[strings.ToLower(key)] {
-		case typeDate:
-			v, ok := value.(time.Time)
-			if ok {
-				result[key] = v.Format("2006-01-02")
+		return string(bs), nil
+	}
+
+	switch columnType {
+	case typeDate:
+		v, ok := value.(time.Time)
+		if ok {
+			return v.Format(dateLayout), nil
+		}
 
-				continue
+		valueStr, ok := value.(string)
+		if ok {
+			timeValue, err := w.parseToTime(valueStr)
+			if err != nil {
+				return nil, fmt.Errorf("convert value to time.Time: %w", err)
 			}
 
-			valueStr, ok := value.(string)
-			if ok {
-				timeValue, err := w.parseToTime(valueStr)
-				if err != nil {
-					return nil, fmt.Errorf("convert value to time.Time: %w", err)
-				}
+			return timeValue.Format(dateLayout), nil
+		}
 
-				result[key] = timeValue.Format("2006-01-02")
+		return nil, ErrInvalidTypeForDateColumn
+	case typeTimestamp:
+		v, ok := value.(time.Time)
+		if ok {
+			// firebolt timestamps have no offset, so the time is written in UTC.
+			// https://docs.firebolt.io/general-reference/data-types.html#date-and-time
+			return v.UTC().Format(timestampLayout), nil
+		}
 
-				continue
+		valueStr, ok := value.(string)
+		if ok {
+			timeValue, err := w.parseToTime(valueStr)
+			if err != nil {
+				return nil, fmt.Errorf("convert value to time.Time: %w", err)
 			}
 
-			return nil, ErrInvalidTypeForDateColumn
-		case typeTimestamp:
-			v, ok := value.(time.Time)
-			if ok {
-				// firebolt date type support this format
-				// https://docs.firebolt.io/general-reference/data-types.html#date-and-time
-				result[key] = v.Format("2006-01-02 15:04:05")
+			// https://docs.firebolt.io/general-reference/data-types.html#timestamp
+			return timeValue.UTC().Format(timestampLayout), nil
+		}
 
-				continue
-			}
+		return nil, ErrInvalidTypeForTimestampColumn
+	case typeInt, typeInteger, typeBigInt, typeLong:
+		return convertInteger(value)
+	default:
+		return value, nil
+	}
+}
 
-			valueStr, ok := value.(string)
-			if ok {
-				timeValue, err := w.parseToTime(valueStr)
-				if err != nil {
-					return nil, fmt.Errorf("convert value to time.Time: %w", err)
-				}
+// convertArray converts the elements of the slice, or of the JSON array string, to the element type of the column.
+func (w *Writer) convertArray(value any, elemType string) ([]any, error) {
+	if s, ok := value.(string); ok {
+		var items []any
+		if err := unmarshalJSON([]byte(s), &items); err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTypeForArrayColumn, s)
+		}
 
-				// firebolt timestamp type support this format
-				// https://docs.firebolt.io/general-reference/data-types.html#timestamp
-				result[key] = timeValue.Format("2006-01-02 15:04:05")
+		value = items
+	}
 
-				continue
-			}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%w: %v (%T)", ErrInvalidTypeForArrayColumn, value, value)
+	}
+
+	result := make([]any, rv.Len())
 
-			return nil, ErrInvalidTypeForTimestampColumn
-		default:
-			result[key] = value
+	for i := range result {
+		var err error
+		if result[i], err = w.convertValue(rv.Index(i).Interface(), elemType); err != nil {
+			return nil, fmt.Errorf("array element %d: %w", i, err)
 		}
 	}
 
 	return result, nil
 }
 
+// arrayElementType returns the element type of the array column type, e.g. int for array(int null).
+func arrayElementType(columnType string) (string, bool) {
+	inner, ok := strings.CutPrefix(columnType, typeArray+"(")
+	if !ok || !strings.HasSuffix(inner, ")") {
+		return "", false
+	}
+
+	inner = strings.TrimSpace(strings.TrimSuffix(inner, ")"))
+
+	// the nullability of the elements doesn't change their conversion.
+	if nested, ok := strings.CutPrefix(inner, "nullable("); ok && strings.HasSuffix(nested, ")") {
+		return strings.TrimSuffix(nested, ")"), true
+	}
+
+	inner = strings.TrimSuffix(inner, " not null")
+	inner = strings.TrimSuffix(inner, " null")
+
+	return inner, true
+}
+
+// convertInteger converts the numbers and the numeric strings decoded from JSON to int64, or to uint64 and *big.Int
+// if they're out of the int64 range, so they're written exactly. The values of other types are returned as is.
+func convertInteger(value any) (any, error) {
+	var (
+		r  *big.Rat
+		ok bool
+	)
+
+	switch v := value.(type) {
+	case json.Number:
+		r, ok = new(big.Rat).SetString(v.String())
+	case string:
+		r, ok = new(big.Rat).SetString(v)
+	case float64:
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			r, ok = new(big.Rat).SetFloat64(v), true
+		}
+	default:
+		return value, nil
+	}
+
+	if !ok || !r.IsInt() {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidValueForIntegerColumn, value)
+	}
+
+	switch n := r.Num(); {
+	case n.IsInt64():
+		return n.Int64(), nil
+	case n.IsUint64():
+		return n.Uint64(), nil
+	default:
+		return n, nil
+	}
+}
+
 func (w *Writer) parseToTime(val string) (time.Time, error) {
 	for _, l := range layouts {
 		timeValue, err := time.Parse(l, val)
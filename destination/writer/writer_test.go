@@ -0,0 +1,1113 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+	"github.com/conduitio-labs/conduit-connector-firebolt/client/fireboltest"
+)
+
+func TestWriter_UpdateDeleteRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		keyColumns []string
+		record     sdk.Record
+		want       [][]any
+		wantErr    bool
+	}{
+		{
+			name: "update by structured key",
+			record: sdk.Record{
+				Operation: sdk.OperationUpdate,
+				Key:       sdk.StructuredData{"ID": 1},
+				Payload:   sdk.Change{After: sdk.StructuredData{"id": 1, "name": "one updated"}},
+			},
+			want: [][]any{{int64(1), "one updated"}, {int64(2), "two"}},
+		},
+		{
+			name: "update by JSON object raw key",
+			record: sdk.Record{
+				Operation: sdk.OperationUpdate,
+				Key:       sdk.RawData(`{"id":2}`),
+				Payload:   sdk.Change{After: sdk.StructuredData{"name": "two updated"}},
+			},
+			want: [][]any{{int64(1), "one"}, {int64(2), "two updated"}},
+		},
+		{
+			name:       "update by raw key value",
+			keyColumns: []string{"ID"},
+			record: sdk.Record{
+				Operation: sdk.OperationUpdate,
+				Key:       sdk.RawData("2"),
+				Payload:   sdk.Change{After: sdk.StructuredData{"name": "two updated"}},
+			},
+			want: [][]any{{int64(1), "one"}, {int64(2), "two updated"}},
+		},
+		{
+			name:       "delete by key column from payload",
+			keyColumns: []string{"id"},
+			record: sdk.Record{
+				Operation: sdk.OperationDelete,
+				Key:       sdk.StructuredData{"name": "one"},
+				Payload:   sdk.Change{Before: sdk.StructuredData{"id": 1, "name": "one"}},
+			},
+			want: [][]any{{int64(2), "two"}},
+		},
+		{
+			name: "delete without key",
+			record: sdk.Record{
+				Operation: sdk.OperationDelete,
+			},
+			wantErr: true,
+		},
+		{
+			name:       "raw key value without a single key column",
+			keyColumns: []string{"id", "name"},
+			record: sdk.Record{
+				Operation: sdk.OperationDelete,
+				Key:       sdk.RawData("1"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			srv := fireboltest.NewServer()
+			defer srv.Close()
+
+			for _, query := range []string{
+				"CREATE DIMENSION TABLE users (id INT, name TEXT)",
+				"INSERT INTO users VALUES (1, 'one'), (2, 'two')",
+			} {
+				if err := srv.Exec(fireboltest.DB, query); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cl := newTestClient(ctx, t, srv)
+
+			w, err := NewWriter(Params{Client: cl, Table: "users", KeyColumns: tt.keyColumns})
+			if err != nil {
+				t.Fatalf("new writer: %v", err)
+			}
+
+			if tt.record.Operation == sdk.OperationDelete {
+				err = w.DeleteRecord(ctx, tt.record)
+			} else {
+				err = w.UpdateRecord(ctx, tt.record)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("write error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			rows, err := cl.GetRows(ctx, client.GetRowsParams{
+				Table:           "users",
+				OrderingColumns: []string{"id"},
+				Limit:           10,
+			})
+			if err != nil {
+				t.Fatalf("get rows: %v", err)
+			}
+
+			got := make([][]any, len(rows))
+			for i, row := range rows {
+				got[i] = []any{row["id"], row["name"]}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_InsertRecords_Upsert(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	for _, query := range []string{
+		"CREATE DIMENSION TABLE users (id INT, name TEXT)",
+		"INSERT INTO users VALUES (1, 'one'), (2, 'two')",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl := newTestClient(ctx, t, srv)
+
+	w, err := NewWriter(Params{Client: cl, Table: "users", MaxInsertRows: 2, Upsert: true})
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+
+	record := func(id int, name string) sdk.Record {
+		return sdk.Record{
+			Operation: sdk.OperationSnapshot,
+			Key:       sdk.StructuredData{"id": id},
+			Payload:   sdk.Change{After: sdk.StructuredData{"id": id, "name": name}},
+		}
+	}
+
+	records := []sdk.Record{
+		record(2, "two updated"),
+		record(3, "three"),
+		record(3, "three updated"),
+		record(4, "four"),
+	}
+
+	// writing the records twice, e.g. after a restart, doesn't create duplicates.
+	for range 2 {
+		n, err := w.InsertRecords(ctx, records)
+		if err != nil {
+			t.Fatalf("insert records: %v", err)
+		}
+
+		if n != len(records) {
+			t.Errorf("inserted = %d, want %d", n, len(records))
+		}
+	}
+
+	rows, err := cl.GetRows(ctx, client.GetRowsParams{
+		Table:           "users",
+		OrderingColumns: []string{"id"},
+		Limit:           10,
+	})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	got := make([][]any, len(rows))
+	for i, row := range rows {
+		got[i] = []any{row["id"], row["name"]}
+	}
+
+	want := [][]any{{int64(1), "one"}, {int64(2), "two updated"}, {int64(3), "three updated"}, {int64(4), "four"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %v, want %v", got, want)
+	}
+}
+
+func TestWriter_BigIntegers(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	if err := srv.Exec(fireboltest.DB, "CREATE DIMENSION TABLE users (id BIGINT, balance BIGINT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	cl := newTestClient(ctx, t, srv)
+
+	w, err := NewWriter(Params{Client: cl, Table: "users", KeyColumns: []string{"id"}})
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+
+	columnTypes, err := cl.GetColumnTypes(ctx, "users")
+	if err != nil {
+		t.Fatalf("get column types: %v", err)
+	}
+
+	w.SetColumnTypes(columnTypes)
+
+	// the values are out of the float64 precision, 9007199254740993 would become 9007199254740992.
+	records := []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.RawData(`{"id":9007199254740993,"balance":-9223372036854775808}`)},
+	}, {
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.RawData(`{"id":9007199254740995,"balance":1}`)},
+	}}
+
+	if _, err = w.InsertRecords(ctx, records); err != nil {
+		t.Fatalf("insert records: %v", err)
+	}
+
+	err = w.UpdateRecord(ctx, sdk.Record{
+		Operation: sdk.OperationUpdate,
+		Key:       sdk.RawData(`9007199254740995`),
+		Payload:   sdk.Change{After: sdk.RawData(`{"balance":9223372036854775807}`)},
+	})
+	if err != nil {
+		t.Fatalf("update record: %v", err)
+	}
+
+	rows, err := cl.GetRows(ctx, client.GetRowsParams{
+		Table:           "users",
+		OrderingColumns: []string{"id"},
+		Limit:           10,
+	})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	got := make([][]any, len(rows))
+	for i, row := range rows {
+		got[i] = []any{row["id"], row["balance"]}
+	}
+
+	want := [][]any{
+		{int64(9007199254740993), int64(math.MinInt64)},
+		{int64(9007199254740995), int64(math.MaxInt64)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %v, want %v", got, want)
+	}
+}
+
+func TestWriter_InsertRecords_Timestamps(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	if err := srv.Exec(fireboltest.DB, "CREATE DIMENSION TABLE events (id BIGINT, created_at TIMESTAMP)"); err != nil {
+		t.Fatal(err)
+	}
+
+	cl := newTestClient(ctx, t, srv)
+
+	w, err := NewWriter(Params{Client: cl, Table: "events"})
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+
+	columnTypes, err := cl.GetColumnTypes(ctx, "events")
+	if err != nil {
+		t.Fatalf("get column types: %v", err)
+	}
+
+	w.SetColumnTypes(columnTypes)
+
+	records := []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload: sdk.Change{After: sdk.StructuredData{
+			"id":         1,
+			"created_at": time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.FixedZone("", 2*60*60)),
+		}},
+	}, {
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.RawData(`{"id":2,"created_at":"2024-01-02T03:04:05.654321-05:00"}`)},
+	}}
+
+	if _, err = w.InsertRecords(ctx, records); err != nil {
+		t.Fatalf("insert records: %v", err)
+	}
+
+	rows, err := cl.GetRows(ctx, client.GetRowsParams{
+		Table:           "events",
+		OrderingColumns: []string{"id"},
+		Limit:           10,
+	})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	got := make([]time.Time, len(rows))
+	for i, row := range rows {
+		got[i], _ = row["created_at"].(time.Time)
+	}
+
+	want := []time.Time{
+		time.Date(2024, 1, 2, 1, 4, 5, 123456000, time.UTC),
+		time.Date(2024, 1, 2, 8, 4, 5, 654321000, time.UTC),
+	}
+	for i := range want {
+		if i >= len(got) || !got[i].Equal(want[i]) {
+			t.Errorf("rows = %v, want %v", got, want)
+
+			break
+		}
+	}
+}
+
+func TestWriter_InsertRecords_ArrayColumns(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	err := srv.Exec(fireboltest.DB, "CREATE DIMENSION TABLE events "+
+		"(id INT, tags ARRAY(TEXT), scores ARRAY(INT NULL), matrix ARRAY(ARRAY(BIGINT)), days ARRAY(DATE), note TEXT NULL)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cl := newTestClient(ctx, t, srv)
+
+	w, err := NewWriter(Params{Client: cl, Table: "events"})
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+
+	columnTypes, err := cl.GetColumnTypes(ctx, "events")
+	if err != nil {
+		t.Fatalf("get column types: %v", err)
+	}
+
+	w.SetColumnTypes(columnTypes)
+
+	day := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	records := []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload: sdk.Change{After: sdk.RawData(`{"id":1,"tags":["a","it's"],"scores":[1,null,3],` +
+			`"matrix":[[9007199254740993],[]],"days":["2022-01-02T00:00:00Z"],"note":["x"]}`)},
+	}, {
+		Operation: sdk.OperationCreate,
+		Payload: sdk.Change{After: sdk.StructuredData{
+			"id":     2,
+			"tags":   []string{"b"},
+			"scores": "[4]",
+			"matrix": [][]int64{{1, 2}},
+			"days":   []time.Time{day},
+			"note":   nil,
+		}},
+	}}
+
+	if _, err = w.InsertRecords(ctx, records); err != nil {
+		t.Fatalf("insert records: %v", err)
+	}
+
+	_, err = w.InsertRecords(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.StructuredData{"id": 3, "tags": "not an array"}},
+	}})
+	if !errors.Is(err, ErrInvalidTypeForArrayColumn) {
+		t.Errorf("insert error = %v, want %v", err, ErrInvalidTypeForArrayColumn)
+	}
+
+	rows, err := cl.GetRows(ctx, client.GetRowsParams{
+		Table:           "events",
+		OrderingColumns: []string{"id"},
+		Limit:           10,
+	})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	want := []map[string]any{{
+		"id":     int64(1),
+		"tags":   []string{"a", "it's"},
+		"scores": []any{int64(1), nil, int64(3)},
+		"matrix": [][]int64{{9007199254740993}, {}},
+		"days":   []time.Time{day},
+		"note":   `["x"]`,
+	}, {
+		"id":     int64(2),
+		"tags":   []string{"b"},
+		"scores": []any{int64(4)},
+		"matrix": [][]int64{{1, 2}},
+		"days":   []time.Time{day},
+		"note":   nil,
+	}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+}
+
+func TestConvertInteger(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    any
+		wantErr bool
+	}{
+		{name: "json number", value: json.Number("9007199254740993"), want: int64(9007199254740993)},
+		{name: "exponent", value: json.Number("1e3"), want: int64(1000)},
+		{name: "uint64", value: json.Number("18446744073709551615"), want: uint64(math.MaxUint64)},
+		{name: "big int", value: json.Number("-9223372036854775809"), want: big.NewInt(0).Sub(
+			big.NewInt(math.MinInt64), big.NewInt(1))},
+		{name: "float64", value: float64(42), want: int64(42)},
+		{name: "numeric string", value: "-7", want: int64(-7)},
+		{name: "int", value: 5, want: 5},
+		{name: "fraction", value: json.Number("1.5"), wantErr: true},
+		{name: "not a number", value: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertInteger(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("convert error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("convert = %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestInferColumnType(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "integer", value: json.Number("1"), want: "BIGINT"},
+		{name: "fraction", value: 1.5, want: "DOUBLE"},
+		{name: "timestamp string", value: "2022-06-01T10:00:00Z", want: "TIMESTAMP"},
+		{name: "object", value: map[string]any{"a": 1}, want: "TEXT"},
+		{name: "nil", value: nil, want: "TEXT"},
+		{name: "array of strings", value: []any{"a", nil, "b"}, want: "ARRAY(TEXT NULL)"},
+		{name: "typed slice", value: []int64{1, 2}, want: "ARRAY(BIGINT NULL)"},
+		{name: "mixed numbers", value: []any{json.Number("1"), json.Number("1.5")}, want: "ARRAY(DOUBLE NULL)"},
+		{name: "nested array", value: []any{[]any{true}}, want: "ARRAY(ARRAY(BOOLEAN NULL) NULL)"},
+		{name: "empty array", value: []any{}, want: "ARRAY(TEXT NULL)"},
+		{name: "mixed types", value: []any{"a", json.Number("1")}, want: "TEXT"},
+		{name: "bytes", value: []byte("abc"), want: "TEXT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inferColumnType(tt.value); got != tt.want {
+				t.Errorf("infer column type = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_InsertRecords_AutoCreateTable(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	cl := newTestClient(ctx, t, srv)
+
+	w, err := NewWriter(Params{
+		Client:          cl,
+		Table:           "users",
+		MaxInsertRows:   10,
+		MaxInsertBytes:  1 << 20,
+		AutoCreateTable: true,
+		Dimension:       true,
+	})
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+
+	records := []sdk.Record{
+		{
+			Operation: sdk.OperationCreate,
+			Key:       sdk.StructuredData{"ID": 1},
+			Payload: sdk.Change{After: sdk.StructuredData{
+				"ID": 1, "name": "one", "score": 1.5, "active": true, "created_at": "2022-06-01T10:00:00Z",
+			}},
+		},
+		{
+			Operation: sdk.OperationCreate,
+			Key:       sdk.StructuredData{"ID": 2},
+			Payload:   sdk.Change{After: sdk.StructuredData{"ID": 2, "name": "two"}},
+		},
+	}
+
+	n, err := w.InsertRecords(ctx, records)
+	if err != nil {
+		t.Fatalf("insert records: %v", err)
+	}
+
+	if n != len(records) {
+		t.Errorf("inserted = %d, want %d", n, len(records))
+	}
+
+	columnTypes, err := cl.GetColumnTypes(ctx, "users")
+	if err != nil {
+		t.Fatalf("get column types: %v", err)
+	}
+
+	// the columns are created with the names of the fields as is.
+	wantTypes := map[string]string{
+		"ID":         "bigint",
+		"active":     "boolean",
+		"created_at": "timestamp",
+		"name":       "text",
+		"score":      "double",
+	}
+	if !reflect.DeepEqual(columnTypes, wantTypes) {
+		t.Errorf("column types = %v, want %v", columnTypes, wantTypes)
+	}
+
+	primaryKeys, err := cl.GetPrimaryKeys(ctx, "users")
+	if err != nil {
+		t.Fatalf("get primary keys: %v", err)
+	}
+
+	if !reflect.DeepEqual(primaryKeys, []string{"ID"}) {
+		t.Errorf("primary keys = %v, want %v", primaryKeys, []string{"ID"})
+	}
+
+	rows, err := cl.GetRows(ctx, client.GetRowsParams{
+		Table:           "users",
+		OrderingColumns: []string{"ID"},
+		Limit:           10,
+	})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	got := make([][]any, len(rows))
+	for i, row := range rows {
+		got[i] = []any{row["ID"], row["name"], row["score"]}
+	}
+
+	want := [][]any{{int64(1), "one", 1.5}, {int64(2), "two", nil}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %v, want %v", got, want)
+	}
+}
+
+func TestWriter_InsertRecords_AutoCreateTableFromSchema(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	cl := newTestClient(ctx, t, srv)
+
+	w, err := NewWriter(Params{
+		Client:          cl,
+		Table:           "users",
+		KeyColumns:      []string{"id"},
+		AutoCreateTable: true,
+		Dimension:       true,
+		PrimaryIndex:    []string{"id"},
+	})
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+
+	// the types of the schema columns are used, the other columns are inferred from the payload.
+	schema := `{"table":"src","version":"1","columns":[` +
+		`{"name":"id","type":"Int32","nullable":false},` +
+		`{"name":"amount","type":"Decimal(18, 2)","nullable":true},` +
+		`{"name":"tags","type":"Array(Nullable(String))","nullable":false}]}`
+
+	records := []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Metadata:  sdk.Metadata{"firebolt.schema": schema},
+		Payload: sdk.Change{After: sdk.RawData(
+			`{"id":1,"amount":"12.50","tags":["a","b"],"scores":[1,2.5]}`,
+		)},
+	}}
+
+	if _, err = w.InsertRecords(ctx, records); err != nil {
+		t.Fatalf("insert records: %v", err)
+	}
+
+	columnTypes, err := cl.GetColumnTypes(ctx, "users")
+	if err != nil {
+		t.Fatalf("get column types: %v", err)
+	}
+
+	wantTypes := map[string]string{
+		"id":     "int",
+		"amount": "decimal(18, 2)",
+		"tags":   "array(text null)",
+		"scores": "array(double null)",
+	}
+	if !reflect.DeepEqual(columnTypes, wantTypes) {
+		t.Errorf("column types = %v, want %v", columnTypes, wantTypes)
+	}
+}
+
+func TestWriter_InsertRecords_SchemaEvolution(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+		want   [][]any
+		// wantTypes holds the column types of the table after the records are written.
+		wantTypes map[string]string
+		wantErr   bool
+	}{
+		{
+			name:    "unknown field fails the record",
+			params:  Params{},
+			want:    [][]any{{int64(1), "one"}},
+			wantErr: true,
+		},
+		{
+			name:   "add columns",
+			params: Params{AddColumns: true},
+			want:   [][]any{{int64(1), "one", nil}, {int64(2), "two", int64(42)}},
+			wantTypes: map[string]string{
+				"id":   "int",
+				"name": "text",
+				"Age":  "bigint",
+			},
+		},
+		{
+			name:   "ignore unknown fields",
+			params: Params{IgnoreUnknownColumns: true},
+			want:   [][]any{{int64(1), "one"}, {int64(2), "two"}},
+			wantTypes: map[string]string{
+				"id":   "int",
+				"name": "text",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			srv := fireboltest.NewServer()
+			defer srv.Close()
+
+			for _, query := range []string{
+				"CREATE DIMENSION TABLE users (id INT, name TEXT)",
+				"INSERT INTO users VALUES (1, 'one')",
+			} {
+				if err := srv.Exec(fireboltest.DB, query); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cl := newTestClient(ctx, t, srv)
+
+			params := tt.params
+			params.Client = cl
+			params.Table = "users"
+			params.MaxInsertRows = 10
+			params.MaxInsertBytes = 1 << 20
+
+			w, err := NewWriter(params)
+			if err != nil {
+				t.Fatalf("new writer: %v", err)
+			}
+
+			columnTypes, err := cl.GetColumnTypes(ctx, "users")
+			if err != nil {
+				t.Fatalf("get column types: %v", err)
+			}
+
+			w.SetColumnTypes(columnTypes)
+
+			_, err = w.InsertRecords(ctx, []sdk.Record{{
+				Operation: sdk.OperationCreate,
+				Payload:   sdk.Change{After: sdk.StructuredData{"id": 2, "name": "two", "Age": 42}},
+			}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("insert records error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			gotTypes, _ := w.columnTypes.get("users")
+			if tt.wantTypes != nil && !reflect.DeepEqual(gotTypes, tt.wantTypes) {
+				t.Errorf("column types = %v, want %v", gotTypes, tt.wantTypes)
+			}
+
+			rows, err := cl.GetRows(ctx, client.GetRowsParams{
+				Table:           "users",
+				OrderingColumns: []string{"id"},
+				Limit:           10,
+			})
+			if err != nil {
+				t.Fatalf("get rows: %v", err)
+			}
+
+			got := make([][]any, len(rows))
+			for i, row := range rows {
+				got[i] = []any{row["id"], row["name"]}
+				if age, ok := row["Age"]; ok {
+					got[i] = append(got[i], age)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_InsertRecords_SchemaErrorInvalidatesColumnTypes(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	if err := srv.Exec(fireboltest.DB, "CREATE DIMENSION TABLE users (id INT, name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	cl := newTestClient(ctx, t, srv)
+
+	w, err := NewWriter(Params{Client: cl, Table: "users", IgnoreUnknownColumns: true})
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+
+	columnTypes, err := cl.GetColumnTypes(ctx, "users")
+	if err != nil {
+		t.Fatalf("get column types: %v", err)
+	}
+
+	w.SetColumnTypes(columnTypes)
+
+	// the table is recreated without the name column, so the cached column types are outdated.
+	for _, query := range []string{"DROP TABLE users", "CREATE DIMENSION TABLE users (id INT)"} {
+		if err = srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records := []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.StructuredData{"id": 1, "name": "one"}},
+	}}
+
+	if _, err = w.InsertRecords(ctx, records); !client.IsSchemaError(err) {
+		t.Fatalf("insert records error = %v, want a schema error", err)
+	}
+
+	if _, ok := w.columnTypes.get("users"); ok {
+		t.Fatal("the column types are cached after the schema error")
+	}
+
+	// the column types are loaded again, so the unknown field is dropped when the record is retried.
+	if _, err = w.InsertRecords(ctx, records); err != nil {
+		t.Fatalf("retry insert records: %v", err)
+	}
+
+	rows, err := cl.GetRows(ctx, client.GetRowsParams{Table: "users", OrderingColumns: []string{"id"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	want := []map[string]any{{"id": int64(1)}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+}
+
+func TestWriter_InsertRecords_RoutedTable(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	for _, query := range []string{
+		"CREATE DIMENSION TABLE users (id INT, name TEXT)",
+		"CREATE DIMENSION TABLE events (id INT, day DATE)",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl := newTestClient(ctx, t, srv)
+
+	w, err := NewWriter(Params{Client: cl, Table: "users", MaxInsertRows: 10, MaxInsertBytes: 1 << 20})
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+
+	record := func(payload sdk.StructuredData) sdk.Record {
+		return sdk.Record{
+			Operation: sdk.OperationCreate,
+			Metadata:  map[string]string{metadataTable: "events"},
+			Payload:   sdk.Change{After: payload},
+		}
+	}
+
+	// the date is converted according to the column type of the routed table.
+	records := []sdk.Record{record(sdk.StructuredData{"id": 1, "day": "Wed, 01 Jun 2022 10:00:00 UTC"})}
+	if _, err = w.InsertRecords(ctx, records); err != nil {
+		t.Fatalf("insert records: %v", err)
+	}
+
+	if _, ok := w.columnTypes.get("events"); !ok {
+		t.Fatal("column types of the routed table aren't cached")
+	}
+
+	// the schema error invalidates the cached column types.
+	_, err = w.InsertRecords(ctx, []sdk.Record{record(sdk.StructuredData{"id": 2, "unknown": "value"})})
+	if err == nil {
+		t.Fatal("insert records error = nil, want schema error")
+	}
+
+	if _, ok := w.columnTypes.get("events"); ok {
+		t.Error("column types of the routed table aren't invalidated")
+	}
+
+	rows, err := cl.GetRows(ctx, client.GetRowsParams{Table: "events", OrderingColumns: []string{"id"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	day := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	if len(rows) != 1 || !reflect.DeepEqual(rows[0]["day"], day) {
+		t.Errorf("rows = %v, want a row with the day %v", rows, day)
+	}
+}
+
+func TestWriter_InsertRecords_TableSettings(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	if err := srv.Exec(fireboltest.DB, "CREATE DIMENSION TABLE users (id INT, name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	cl := newTestClient(ctx, t, srv)
+
+	w, err := NewWriter(Params{
+		Client:         cl,
+		Table:          "users",
+		MaxInsertRows:  10,
+		MaxInsertBytes: 1 << 20,
+		Tables: map[string]TableParams{
+			"Events": {KeyColumns: []string{"ID"}, Upsert: true, AutoCreateTable: true, Dimension: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+
+	record := func(table string, id int, name string) sdk.Record {
+		return sdk.Record{
+			Operation: sdk.OperationCreate,
+			Metadata:  map[string]string{metadataTable: table},
+			Payload:   sdk.Change{After: sdk.StructuredData{"id": id, "name": name}},
+		}
+	}
+
+	// the events table is created and upserted by the key column, the users table keeps the insert mode.
+	records := []sdk.Record{
+		record("events", 1, "created"),
+		record("users", 1, "one"),
+		record("events", 1, "updated"),
+		record("users", 1, "one again"),
+	}
+
+	if _, err = w.InsertRecords(ctx, records); err != nil {
+		t.Fatalf("insert records: %v", err)
+	}
+
+	for table, want := range map[string][]string{
+		"events": {"updated"},
+		"users":  {"one", "one again"},
+	} {
+		rows, err := cl.GetRows(ctx, client.GetRowsParams{
+			Table:           table,
+			OrderingColumns: []string{"id", "name"},
+			Limit:           10,
+		})
+		if err != nil {
+			t.Fatalf("get rows of %q: %v", table, err)
+		}
+
+		got := make([]string, len(rows))
+		for i, row := range rows {
+			got[i] = row["name"].(string)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("names of %q = %v, want %v", table, got, want)
+		}
+	}
+}
+
+func TestWriter_ColumnMapping(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	err := srv.Exec(fireboltest.DB, "CREATE DIMENSION TABLE users (user_id INT, name TEXT, city TEXT NULL)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cl := newTestClient(ctx, t, srv)
+
+	w, err := NewWriter(Params{
+		Client:         cl,
+		Table:          "users",
+		MaxInsertRows:  10,
+		MaxInsertBytes: 1 << 20,
+		ColumnMapping: ColumnMapping{
+			Columns:       map[string]string{"userId": "user_id", "userName": "name", "address.city": "city"},
+			ExcludeFields: []string{"password", "address"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+
+	records := []sdk.Record{
+		{
+			Operation: sdk.OperationCreate,
+			Payload: sdk.Change{After: sdk.StructuredData{
+				"userId": 1, "userName": "one", "password": "secret", "address": map[string]any{"city": "Kyiv"},
+			}},
+		},
+		{
+			Operation: sdk.OperationCreate,
+			Payload:   sdk.Change{After: sdk.StructuredData{"userId": 2, "userName": "two"}},
+		},
+	}
+
+	if _, err = w.InsertRecords(ctx, records); err != nil {
+		t.Fatalf("insert records: %v", err)
+	}
+
+	// the key fields are renamed to their columns too.
+	err = w.UpdateRecord(ctx, sdk.Record{
+		Operation: sdk.OperationUpdate,
+		Key:       sdk.StructuredData{"userId": 2},
+		Payload: sdk.Change{After: sdk.StructuredData{
+			"userId": 2, "userName": "two", "address": map[string]any{"city": "Lviv"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("update record: %v", err)
+	}
+
+	rows, err := cl.GetRows(ctx, client.GetRowsParams{Table: "users", OrderingColumns: []string{"user_id"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	got := make([][]any, len(rows))
+	for i, row := range rows {
+		got[i] = []any{row["user_id"], row["name"], row["city"]}
+	}
+
+	want := [][]any{{int64(1), "one", "Kyiv"}, {int64(2), "two", "Lviv"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %v, want %v", got, want)
+	}
+}
+
+func TestWriter_getTableName(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		record   sdk.Record
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:   "metadata table",
+			record: sdk.Record{Metadata: map[string]string{metadataTable: "Events"}},
+			want:   "Events",
+		},
+		{
+			name:   "configured table",
+			record: sdk.Record{},
+			want:   "users",
+		},
+		{
+			name:     "template of metadata",
+			template: `{{ index .Metadata "opencdc.collection" }}_raw`,
+			record:   sdk.Record{Metadata: map[string]string{"opencdc.collection": "Orders", metadataTable: "events"}},
+			want:     "Orders_raw",
+		},
+		{
+			name:     "template of payload",
+			template: `events_{{ .Payload.After.tenant }}`,
+			record:   sdk.Record{Payload: sdk.Change{After: sdk.StructuredData{"tenant": 42}}},
+			want:     "events_42",
+		},
+		{
+			name:     "empty result",
+			template: `{{ index .Metadata "opencdc.collection" }}`,
+			record:   sdk.Record{Metadata: map[string]string{metadataTable: "events"}},
+			wantErr:  true,
+		},
+		{
+			name:     "missing payload field",
+			template: `{{ .Payload.After.tenant }}`,
+			record:   sdk.Record{Payload: sdk.Change{After: sdk.StructuredData{"id": 1}}},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid identifier",
+			template: `{{ index .Metadata "opencdc.collection" }}`,
+			record:   sdk.Record{Metadata: map[string]string{"opencdc.collection": "orders\x00"}},
+			wantErr:  true,
+		},
+		{
+			name:     "execution error",
+			template: `{{ .Payload.After.tenant.id }}`,
+			record:   sdk.Record{Payload: sdk.Change{After: sdk.RawData("tenant")}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := NewWriter(Params{Table: "users", TableTemplate: tt.template})
+			if err != nil {
+				t.Fatalf("new writer: %v", err)
+			}
+
+			got, err := w.getTableName(tt.record)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("get table name error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("get table name = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestClient(ctx context.Context, t *testing.T, srv *fireboltest.Server) *client.Client {
+	t.Helper()
+
+	cl := client.New(ctx, srv.URL, fireboltest.DB)
+	t.Cleanup(func() { cl.Close(ctx) })
+
+	err := cl.Login(ctx, client.LoginParams{
+		Email:       fireboltest.Email,
+		Password:    fireboltest.Password,
+		AccountName: fireboltest.AccountName,
+		EngineName:  fireboltest.EngineName,
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	return cl
+}
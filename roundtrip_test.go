@@ -0,0 +1,207 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firebolt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+	"github.com/conduitio-labs/conduit-connector-firebolt/client/fireboltest"
+	"github.com/conduitio-labs/conduit-connector-firebolt/config"
+)
+
+// TestRoundTrip_BigIntegers checks that the integers out of the float64 precision are read by the source,
+// kept in its positions and written by the destination exactly.
+func TestRoundTrip_BigIntegers(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	// 9007199254740993 (2^53 + 1) and 9007199254740992 are the same float64 number.
+	for _, query := range []string{
+		"CREATE DIMENSION TABLE src (id BIGINT, amount BIGINT)",
+		"CREATE DIMENSION TABLE dst (id BIGINT, amount BIGINT)",
+		"INSERT INTO src VALUES (9007199254740992, 1), (9007199254740993, -9223372036854775808), " +
+			"(9007199254740994, 9223372036854775807)",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := map[string]string{
+		config.KeyEmail:           fireboltest.Email,
+		config.KeyPassword:        fireboltest.Password,
+		config.KeyAccountName:     fireboltest.AccountName,
+		config.KeyEngineName:      fireboltest.EngineName,
+		config.KeyDB:              fireboltest.DB,
+		config.KeyAPIURL:          srv.URL,
+		config.KeyTable:           "src",
+		config.KeyOrderingColumns: "id",
+		config.KeyBatchSize:       "1",
+	}
+
+	// the source is restarted after the first record, so the rest are read after the position.
+	records := readRecords(ctx, t, cfg, nil, 1)
+	records = append(records, readRecords(ctx, t, cfg, records[0].Position, 2)...)
+
+	// the records of the source are written to the table of their metadata.
+	cfg[config.KeyTable] = "dst"
+	for i := range records {
+		records[i].Metadata["firebolt.table"] = "dst"
+	}
+
+	dest := Connector.NewDestination()
+
+	if err := dest.Configure(ctx, cfg); err != nil {
+		t.Fatalf("configure destination: %v", err)
+	}
+
+	if err := dest.Open(ctx); err != nil {
+		t.Fatalf("open destination: %v", err)
+	}
+
+	defer func() {
+		if err := dest.Teardown(ctx); err != nil {
+			t.Errorf("teardown destination: %v", err)
+		}
+	}()
+
+	if _, err := dest.Write(ctx, records); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cl := client.New(ctx, srv.URL, fireboltest.DB)
+	defer cl.Close(ctx)
+
+	err := cl.Login(ctx, client.LoginParams{
+		Email:       fireboltest.Email,
+		Password:    fireboltest.Password,
+		AccountName: fireboltest.AccountName,
+		EngineName:  fireboltest.EngineName,
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	rows, err := cl.GetRows(ctx, client.GetRowsParams{Table: "dst", OrderingColumns: []string{"id"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("get rows: %v", err)
+	}
+
+	want := []map[string]any{
+		{"id": int64(9007199254740992), "amount": int64(1)},
+		{"id": int64(9007199254740993), "amount": int64(-9223372036854775808)},
+		{"id": int64(9007199254740994), "amount": int64(9223372036854775807)},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+}
+
+// TestSource_ColumnNameCase checks that the configured columns are matched to the columns
+// of the table created without quotes, whatever their case.
+func TestSource_ColumnNameCase(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	for _, query := range []string{
+		"CREATE DIMENSION TABLE users (id BIGINT, name TEXT, age INT)",
+		"INSERT INTO users VALUES (1, 'alice', 30), (2, 'bob', 40)",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := map[string]string{
+		config.KeyEmail:           fireboltest.Email,
+		config.KeyPassword:        fireboltest.Password,
+		config.KeyAccountName:     fireboltest.AccountName,
+		config.KeyEngineName:      fireboltest.EngineName,
+		config.KeyDB:              fireboltest.DB,
+		config.KeyAPIURL:          srv.URL,
+		config.KeyTable:           "Users",
+		config.KeyColumns:         "ID,Name",
+		config.KeyPrimaryKeys:     "ID",
+		config.KeyOrderingColumns: "Id",
+	}
+
+	records := readRecords(ctx, t, cfg, nil, 2)
+
+	for i, want := range []sdk.StructuredData{
+		{"id": int64(1), "name": "alice"},
+		{"id": int64(2), "name": "bob"},
+	} {
+		if !reflect.DeepEqual(records[i].Key, sdk.StructuredData{"id": want["id"]}) {
+			t.Errorf("record %d key = %v, want id %v", i, records[i].Key, want["id"])
+		}
+
+		if !reflect.DeepEqual(records[i].Payload.After, want) {
+			t.Errorf("record %d payload = %v, want %v", i, records[i].Payload.After, want)
+		}
+	}
+}
+
+// readRecords opens the source at the position and reads n records.
+func readRecords(ctx context.Context, t *testing.T, cfg map[string]string, pos sdk.Position, n int) []sdk.Record {
+	t.Helper()
+
+	src := Connector.NewSource()
+
+	if err := src.Configure(ctx, cfg); err != nil {
+		t.Fatalf("configure source: %v", err)
+	}
+
+	if err := src.Open(ctx, pos); err != nil {
+		t.Fatalf("open source: %v", err)
+	}
+
+	defer func() {
+		if err := src.Teardown(ctx); err != nil {
+			t.Errorf("teardown source: %v", err)
+		}
+	}()
+
+	records := make([]sdk.Record, 0, n)
+
+	// the source may ask to back off when it switches from the snapshot to CDC.
+	for attempt := 0; len(records) < n && attempt < 2*n+2; attempt++ {
+		record, err := src.Read(ctx)
+		if errors.Is(err, sdk.ErrBackoffRetry) {
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+
+		records = append(records, record)
+	}
+
+	if len(records) != n {
+		t.Fatalf("read %d records, want %d", len(records), n)
+	}
+
+	return records
+}
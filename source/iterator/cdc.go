@@ -0,0 +1,250 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+	"github.com/conduitio-labs/conduit-connector-firebolt/source/position"
+)
+
+// CDCIterator polls the table for rows which tracking column value is greater than the last processed one.
+type CDCIterator struct {
+	// firebolt client.
+	client *client.Client
+	// batchSize size of batch.
+	batchSize int
+	// currentBatch - rows in current batch from table.
+	currentBatch []map[string]any
+	// name of columns what iterator use for setting key in record.
+	primaryKeys []string
+	// list of columns to reading from table.
+	columns []string
+	// table which iterator read.
+	table string
+	// query - a SELECT query which result the iterator reads instead of the table.
+	query string
+	// filter - a WHERE clause predicate limiting the read rows.
+	filter string
+	// trackingColumn - name of the column which value increases when a row is inserted or updated.
+	trackingColumn string
+	// orderingColumns name of columns that the connector will use for ordering rows.
+	orderingColumns []string
+	// keysetColumns - the tracking column followed by the ordering columns, the rows are read in this order.
+	keysetColumns []string
+	// lastProcessedValues - values of the keyset columns of the last processed row.
+	lastProcessedValues map[string]any
+	// maxOrderingValues - values of the ordering columns of the last created row.
+	maxOrderingValues map[string]any
+	// rawPayload - the record payloads are rows encoded as JSON instead of structured data.
+	rawPayload bool
+	// schemas - the registered schemas of the records.
+	schemas *schemaRegistry
+	// schema - the schema of the rows of the current batch.
+	schema registeredSchema
+}
+
+// NewCDCIterator creates a new CDC iterator.
+func NewCDCIterator(params Params) *CDCIterator {
+	keysetColumns := []string{params.TrackingColumn}
+	for _, col := range params.OrderingColumns {
+		if col != params.TrackingColumn {
+			keysetColumns = append(keysetColumns, col)
+		}
+	}
+
+	return &CDCIterator{
+		client:          params.Client,
+		batchSize:       params.BatchSize,
+		primaryKeys:     params.PrimaryKeys,
+		columns:         params.Columns,
+		table:           params.Table,
+		query:           params.Query,
+		filter:          params.Filter,
+		trackingColumn:  params.TrackingColumn,
+		orderingColumns: params.OrderingColumns,
+		keysetColumns:   keysetColumns,
+		rawPayload:      params.RawPayload,
+		schemas:         newSchemaRegistry(),
+	}
+}
+
+// Setup iterator.
+// If the position is nil, the iterator captures only the changes made after
+// the current maximum value of the tracking column.
+func (i *CDCIterator) Setup(ctx context.Context, p sdk.Position) error {
+	pos, err := position.ParseSDKPosition(p)
+	if err != nil {
+		return err
+	}
+
+	if p != nil {
+		if err = checkFilter(pos, i.filter); err != nil {
+			return err
+		}
+	}
+
+	if pos.Mode == position.ModeCDC {
+		i.lastProcessedValues = pos.LastProcessedValues
+		i.maxOrderingValues = pos.MaxOrderingValues
+
+		err = restoreValues(ctx, i.client, client.GetRowsParams{Table: i.table, Query: i.query, Filter: i.filter},
+			i.lastProcessedValues, i.maxOrderingValues)
+		if err != nil {
+			return fmt.Errorf("restore values: %w", err)
+		}
+	}
+
+	err = validateRows(ctx, i.client, client.GetRowsParams{Table: i.table, Query: i.query, Filter: i.filter},
+		i.orderingColumns, i.trackingColumn)
+	if err != nil {
+		return fmt.Errorf("validate rows: %w", err)
+	}
+
+	i.primaryKeys, err = populatePrimaryKeys(ctx, i.client, i.table, i.query, i.primaryKeys, i.orderingColumns)
+	if err != nil {
+		return fmt.Errorf("populate primary keys: %w", err)
+	}
+
+	if p != nil {
+		return nil
+	}
+
+	maxValue, err := i.client.GetMaxValue(ctx, client.GetMaxValueParams{
+		Table:  i.table,
+		Query:  i.query,
+		Filter: i.filter,
+		Column: i.trackingColumn,
+	})
+	if err != nil {
+		return fmt.Errorf("get max value of %q: %w", i.trackingColumn, err)
+	}
+
+	// the table is empty, all the rows are new.
+	if maxValue == nil {
+		return nil
+	}
+
+	i.lastProcessedValues = map[string]any{i.trackingColumn: maxValue}
+
+	return nil
+}
+
+// HasNext check ability to get next record.
+func (i *CDCIterator) HasNext(ctx context.Context) (bool, error) {
+	if len(i.currentBatch) > 0 {
+		return true, nil
+	}
+
+	rows, columns, err := i.client.GetRowsWithColumns(ctx, client.GetRowsParams{
+		Table:           i.table,
+		Query:           i.query,
+		Filter:          i.filter,
+		Columns:         i.columns,
+		OrderingColumns: i.keysetColumns,
+		After:           i.lastProcessedValues,
+		Limit:           i.batchSize,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if i.schema, err = i.schemas.register(i.table, columns); err != nil {
+		return false, fmt.Errorf("register schema: %w", err)
+	}
+
+	i.currentBatch = rows
+
+	return len(i.currentBatch) > 0, nil
+}
+
+// Next get new record.
+// A row is considered created if it follows the last created row in the ordering, otherwise it's considered updated.
+func (i *CDCIterator) Next(_ context.Context) (sdk.Record, error) {
+	row := i.currentBatch[0]
+
+	lastProcessedValues, err := rowValues(row, i.keysetColumns)
+	if err != nil {
+		return sdk.Record{}, err
+	}
+
+	orderingValues, err := rowValues(row, i.orderingColumns)
+	if err != nil {
+		return sdk.Record{}, err
+	}
+
+	created := i.maxOrderingValues == nil
+	if !created {
+		cmp, er := compareRows(orderingValues, i.maxOrderingValues, i.orderingColumns)
+		if er != nil {
+			return sdk.Record{}, er
+		}
+
+		created = cmp > 0
+	}
+
+	maxOrderingValues := i.maxOrderingValues
+	if created {
+		maxOrderingValues = orderingValues
+	}
+
+	payload, err := recordPayload(row, i.rawPayload)
+	if err != nil {
+		return sdk.Record{}, err
+	}
+
+	key, err := recordKey(row, i.primaryKeys)
+	if err != nil {
+		return sdk.Record{}, err
+	}
+
+	p, err := position.NewCDCPosition(lastProcessedValues, maxOrderingValues, i.filter).ToSDKPosition()
+	if err != nil {
+		return sdk.Record{}, err
+	}
+
+	if len(i.currentBatch) > 1 {
+		i.currentBatch = i.currentBatch[1:]
+	} else {
+		i.currentBatch = nil
+	}
+
+	i.lastProcessedValues = lastProcessedValues
+	i.maxOrderingValues = maxOrderingValues
+
+	if created {
+		return sdk.Util.Source.NewRecordCreate(p, recordMetadata(i.table, i.schema), key, payload), nil
+	}
+
+	return sdk.Util.Source.NewRecordUpdate(p, recordMetadata(i.table, i.schema), key, nil, payload), nil
+}
+
+// Stop shutdown iterator.
+func (i *CDCIterator) Stop(ctx context.Context) error {
+	i.client.Close(ctx)
+
+	return nil
+}
+
+// Ack check if record with position was recorded.
+func (i *CDCIterator) Ack(ctx context.Context, rp sdk.Position) error {
+	sdk.Logger(ctx).Debug().Str("position", string(rp)).Msg("got ack")
+
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client/fireboltest"
+)
+
+func TestCDCIterator(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	for _, query := range []string{
+		"CREATE DIMENSION TABLE users (id INT, name TEXT, updated_at INT)",
+		"INSERT INTO users VALUES (1, 'one', 1), (2, 'two', 1)",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newIterator := func() *CDCIterator {
+		return NewCDCIterator(Params{
+			Client: newTestClient(ctx, t, srv), BatchSize: 2, Table: testTable, TrackingColumn: "updated_at",
+			OrderingColumns: []string{"id"},
+		})
+	}
+
+	it := newIterator()
+
+	// the iterator captures only the changes made after the last processed row.
+	pos := sdk.Position(`{"mode":"cdc","lastProcessedValues":{"updated_at":1,"id":2},"maxOrderingValues":{"id":2}}`)
+	if err := it.Setup(ctx, pos); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	// the rows with the same tracking value are read in the order of the ordering columns.
+	for _, query := range []string{
+		"INSERT INTO users VALUES (4, 'four', 2), (3, 'three', 2)",
+		"INSERT INTO users VALUES (1, 'one updated', 2)",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records := readRecords(ctx, t, it, 2)
+
+	// resume at the position of the last read record.
+	it = newIterator()
+	if err := it.Setup(ctx, records[len(records)-1].Position); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	if err := srv.Exec(fireboltest.DB, "INSERT INTO users VALUES (5, 'five', 3)"); err != nil {
+		t.Fatal(err)
+	}
+
+	records = append(records, readRecords(ctx, t, it, -1)...)
+
+	type change struct {
+		op   sdk.Operation
+		name string
+	}
+
+	got := make([]change, len(records))
+	for i, record := range records {
+		got[i] = change{op: record.Operation, name: name(t, record)}
+	}
+
+	want := []change{
+		{op: sdk.OperationUpdate, name: "one updated"},
+		{op: sdk.OperationCreate, name: "three"},
+		{op: sdk.OperationCreate, name: "four"},
+		{op: sdk.OperationCreate, name: "five"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("read = %v, want %v", got, want)
+	}
+}
+
+func TestCDCIterator_ResumeTimestamp(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	for _, query := range []string{
+		"CREATE DIMENSION TABLE users (id INT, name TEXT, updated_at TIMESTAMP)",
+		"INSERT INTO users VALUES (1, 'one', '2022-01-01 00:00:00')",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newIterator := func() *CDCIterator {
+		return NewCDCIterator(Params{
+			Client: newTestClient(ctx, t, srv), BatchSize: 1, Table: testTable, TrackingColumn: "updated_at",
+			OrderingColumns: []string{"id"},
+		})
+	}
+
+	it := newIterator()
+	if err := it.Setup(ctx, nil); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	err := srv.Exec(fireboltest.DB,
+		"INSERT INTO users VALUES (2, 'two', '2022-01-02 10:00:00.5'), (3, 'three', '2022-01-02 10:00:00.5')")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := readRecords(ctx, t, it, 1)
+
+	// resume at the position of the last read record.
+	it = newIterator()
+	if err = it.Setup(ctx, records[0].Position); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	// the timestamps restored from the position are compared as timestamps, as the ones read from the rows.
+	want := time.Date(2022, 1, 2, 10, 0, 0, 500000000, time.UTC)
+	if got := it.lastProcessedValues["updated_at"]; got != want {
+		t.Errorf("restored value = %v (%T), want %v", got, got, want)
+	}
+
+	records = append(records, readRecords(ctx, t, it, -1)...)
+
+	got := make([]string, len(records))
+	for i, record := range records {
+		got[i] = name(t, record)
+	}
+
+	if wantNames := []string{"two", "three"}; !reflect.DeepEqual(got, wantNames) {
+		t.Errorf("read = %v, want %v", got, wantNames)
+	}
+}
+
+// recordReader is implemented by the iterators.
+type recordReader interface {
+	HasNext(ctx context.Context) (bool, error)
+	Next(ctx context.Context) (sdk.Record, error)
+}
+
+// readRecords reads n records or all the remaining records if n is negative.
+func readRecords(ctx context.Context, t *testing.T, it recordReader, n int) []sdk.Record {
+	t.Helper()
+
+	var records []sdk.Record
+
+	for n < 0 || len(records) < n {
+		hasNext, err := it.HasNext(ctx)
+		if err != nil {
+			t.Fatalf("has next: %v", err)
+		}
+
+		if !hasNext {
+			break
+		}
+
+		record, err := it.Next(ctx)
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
@@ -0,0 +1,132 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/source/position"
+)
+
+// CombinedIterator reads the rows up to the maximum value of the tracking column captured when the snapshot started,
+// and then captures the changes made after that value.
+type CombinedIterator struct {
+	snapshot *SnapshotIterator
+	cdc      *CDCIterator
+	// mode - the mode of the iterator which is currently in use.
+	mode position.Mode
+}
+
+// NewCombinedIterator creates a new combined iterator.
+func NewCombinedIterator(params Params) *CombinedIterator {
+	return &CombinedIterator{
+		snapshot: NewSnapshotIterator(params),
+		cdc:      NewCDCIterator(params),
+		mode:     position.ModeSnapshot,
+	}
+}
+
+// Setup iterator.
+// The position defines whether the iterator continues the snapshot or the changes capturing.
+func (i *CombinedIterator) Setup(ctx context.Context, p sdk.Position) error {
+	pos, err := position.ParseSDKPosition(p)
+	if err != nil {
+		return err
+	}
+
+	if pos.Mode == position.ModeCDC {
+		i.mode = position.ModeCDC
+
+		return i.cdc.Setup(ctx, p)
+	}
+
+	return i.snapshot.Setup(ctx, p)
+}
+
+// HasNext check ability to get next record.
+// The iterator switches to capturing the changes once the snapshot is finished.
+func (i *CombinedIterator) HasNext(ctx context.Context) (bool, error) {
+	if i.mode == position.ModeCDC {
+		return i.cdc.HasNext(ctx)
+	}
+
+	hasNext, err := i.snapshot.HasNext(ctx)
+	if err != nil || hasNext {
+		return hasNext, err
+	}
+
+	if err = i.switchToCDC(ctx); err != nil {
+		return false, fmt.Errorf("switch to cdc: %w", err)
+	}
+
+	return i.cdc.HasNext(ctx)
+}
+
+// Next get new record.
+func (i *CombinedIterator) Next(ctx context.Context) (sdk.Record, error) {
+	if i.mode == position.ModeCDC {
+		return i.cdc.Next(ctx)
+	}
+
+	return i.snapshot.Next(ctx)
+}
+
+// Stop shutdown iterator.
+func (i *CombinedIterator) Stop(ctx context.Context) error {
+	// both iterators use the same client.
+	return i.snapshot.Stop(ctx)
+}
+
+// stop stops reading the table, it doesn't close the client.
+func (i *CombinedIterator) stop() {
+	i.snapshot.stop()
+}
+
+// Ack check if record with position was recorded.
+func (i *CombinedIterator) Ack(ctx context.Context, rp sdk.Position) error {
+	sdk.Logger(ctx).Debug().Str("position", string(rp)).Msg("got ack")
+
+	return nil
+}
+
+// switchToCDC sets up the CDC iterator to continue after the tracking bound of the snapshot.
+// The last snapshot row has the maximum values of the ordering columns,
+// so the CDC iterator considers the rows that follow it as created.
+func (i *CombinedIterator) switchToCDC(ctx context.Context) error {
+	var lastProcessedValues map[string]any
+
+	// the table was empty when the snapshot started, otherwise the changes follow the bound.
+	if i.snapshot.trackingBound != nil {
+		lastProcessedValues = map[string]any{i.snapshot.trackingColumn: i.snapshot.trackingBound}
+	}
+
+	pos := position.NewCDCPosition(lastProcessedValues, i.snapshot.lastProcessedValues, i.snapshot.filter)
+
+	p, err := pos.ToSDKPosition()
+	if err != nil {
+		return fmt.Errorf("convert cdc position: %w", err)
+	}
+
+	if err = i.cdc.Setup(ctx, p); err != nil {
+		return fmt.Errorf("cdc iterator setup: %w", err)
+	}
+
+	i.mode = position.ModeCDC
+
+	return nil
+}
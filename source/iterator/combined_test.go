@@ -0,0 +1,102 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client/fireboltest"
+)
+
+func TestCombinedIterator(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	for _, query := range []string{
+		"CREATE DIMENSION TABLE users (id INT, name TEXT, updated_at INT)",
+		"INSERT INTO users VALUES (1, 'one', 1), (2, 'two', 1)",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newIterator := func() *CombinedIterator {
+		return NewCombinedIterator(Params{
+			Client: newTestClient(ctx, t, srv), BatchSize: 1, Table: testTable, TrackingColumn: "updated_at",
+			OrderingColumns: []string{"id"},
+		})
+	}
+
+	it := newIterator()
+	if err := it.Setup(ctx, nil); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	records := readRecords(ctx, t, it, 1)
+
+	// the rows changed after the snapshot started are captured as changes, not as a part of the snapshot.
+	for _, query := range []string{
+		"INSERT INTO users VALUES (3, 'three', 2)",
+		"INSERT INTO users VALUES (2, 'two updated', 2)",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// resume in the middle of the snapshot, the bound captured at the start is kept.
+	it = newIterator()
+	if err := it.Setup(ctx, records[0].Position); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	records = append(records, readRecords(ctx, t, it, 2)...)
+
+	// resume after switching to capturing the changes.
+	it = newIterator()
+	if err := it.Setup(ctx, records[len(records)-1].Position); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	records = append(records, readRecords(ctx, t, it, -1)...)
+
+	type change struct {
+		op   sdk.Operation
+		name string
+	}
+
+	got := make([]change, len(records))
+	for i, record := range records {
+		got[i] = change{op: record.Operation, name: name(t, record)}
+	}
+
+	want := []change{
+		{op: sdk.OperationSnapshot, name: "one"},
+		{op: sdk.OperationSnapshot, name: "two"},
+		{op: sdk.OperationUpdate, name: "two updated"},
+		{op: sdk.OperationCreate, name: "three"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("read = %v, want %v", got, want)
+	}
+}
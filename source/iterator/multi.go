@@ -0,0 +1,151 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+	"github.com/conduitio-labs/conduit-connector-firebolt/source/position"
+)
+
+// MultiTableIterator reads several tables, each of them by its own combined iterator.
+// The position of a record holds the positions of all the tables, so each of them resumes where it stopped.
+type MultiTableIterator struct {
+	// client - the client shared by the iterators of the tables.
+	client    *client.Client
+	tables    []string
+	iterators []*CombinedIterator
+	// sequential - the iterator reads a table until it has no more rows instead of switching tables after each record.
+	sequential bool
+	// positions - the positions of the last records read from the tables, by the table names.
+	positions map[string]json.RawMessage
+	// current - the index of the table which is read next.
+	current int
+}
+
+// NewMultiTableIterator creates a new iterator of the tables, one per params.
+// The params share the client, it's closed once when the iterator stops.
+func NewMultiTableIterator(params []Params, sequential bool) *MultiTableIterator {
+	it := &MultiTableIterator{
+		tables:     make([]string, len(params)),
+		iterators:  make([]*CombinedIterator, len(params)),
+		sequential: sequential,
+		positions:  make(map[string]json.RawMessage),
+	}
+
+	if len(params) > 0 {
+		it.client = params[0].Client
+	}
+
+	for i := range params {
+		it.tables[i] = params[i].Table
+		it.iterators[i] = NewCombinedIterator(params[i])
+	}
+
+	return it
+}
+
+// Setup iterator.
+// The tables which have no position in the given one, e.g. the tables added to the config, are read from the beginning.
+func (i *MultiTableIterator) Setup(ctx context.Context, p sdk.Position) error {
+	pos, err := position.ParseTablesPosition(p)
+	if err != nil {
+		return fmt.Errorf("parse position: %w", err)
+	}
+
+	for k, table := range i.tables {
+		var tablePosition sdk.Position
+
+		if raw, ok := pos.Tables[table]; ok {
+			tablePosition = sdk.Position(raw)
+			i.positions[table] = raw
+		}
+
+		if err = i.iterators[k].Setup(ctx, tablePosition); err != nil {
+			return fmt.Errorf("table %q: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// HasNext check ability to get next record.
+// It looks for a table having the next record starting with the current one.
+func (i *MultiTableIterator) HasNext(ctx context.Context) (bool, error) {
+	for range i.iterators {
+		hasNext, err := i.iterators[i.current].HasNext(ctx)
+		if err != nil {
+			return false, fmt.Errorf("table %q: %w", i.tables[i.current], err)
+		}
+
+		if hasNext {
+			return true, nil
+		}
+
+		i.current = (i.current + 1) % len(i.iterators)
+	}
+
+	return false, nil
+}
+
+// Next get new record.
+// The record position holds the positions of all the tables.
+func (i *MultiTableIterator) Next(ctx context.Context) (sdk.Record, error) {
+	table := i.tables[i.current]
+
+	record, err := i.iterators[i.current].Next(ctx)
+	if err != nil {
+		return sdk.Record{}, fmt.Errorf("table %q: %w", table, err)
+	}
+
+	i.positions[table] = json.RawMessage(record.Position)
+
+	record.Position, err = position.TablesPosition{Tables: i.positions}.ToSDKPosition()
+	if err != nil {
+		return sdk.Record{}, fmt.Errorf("convert position: %w", err)
+	}
+
+	if !i.sequential {
+		i.current = (i.current + 1) % len(i.iterators)
+	}
+
+	return record, nil
+}
+
+// Stop shutdown iterator.
+// The iterators of the tables are stopped before the shared client is closed.
+func (i *MultiTableIterator) Stop(ctx context.Context) error {
+	for k := range i.iterators {
+		i.iterators[k].stop()
+	}
+
+	if i.client != nil {
+		i.client.Close(ctx)
+	}
+
+	return nil
+}
+
+// Ack check if record with position was recorded.
+func (i *MultiTableIterator) Ack(ctx context.Context, rp sdk.Position) error {
+	sdk.Logger(ctx).Debug().Str("position", string(rp)).Msg("got ack")
+
+	return nil
+}
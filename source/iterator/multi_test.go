@@ -0,0 +1,98 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client/fireboltest"
+)
+
+func TestMultiTableIterator(t *testing.T) {
+	tests := []struct {
+		name       string
+		sequential bool
+		want       []string
+	}{
+		{
+			name: "round robin",
+			want: []string{"u1", "e1", "u2", "e2", "e3"},
+		},
+		{
+			name:       "sequential",
+			sequential: true,
+			want:       []string{"u1", "u2", "e1", "e2", "e3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			srv := fireboltest.NewServer()
+			defer srv.Close()
+
+			for _, query := range []string{
+				"CREATE DIMENSION TABLE users (id INT, name TEXT)",
+				"CREATE DIMENSION TABLE events (seq INT, name TEXT)",
+				"INSERT INTO users VALUES (2, 'u2'), (1, 'u1')",
+				"INSERT INTO events VALUES (1, 'e1'), (3, 'e3'), (2, 'e2')",
+			} {
+				if err := srv.Exec(fireboltest.DB, query); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			newIterator := func() *MultiTableIterator {
+				cl := newTestClient(ctx, t, srv)
+
+				return NewMultiTableIterator([]Params{
+					{Client: cl, BatchSize: 1, Table: testTable, TrackingColumn: "id", OrderingColumns: []string{"id"}},
+					{Client: cl, BatchSize: 1, Table: "events", TrackingColumn: "seq", OrderingColumns: []string{"seq"}},
+				}, tt.sequential)
+			}
+
+			it := newIterator()
+			if err := it.Setup(ctx, nil); err != nil {
+				t.Fatalf("setup: %v", err)
+			}
+
+			records := readRecords(ctx, t, it, 3)
+
+			if err := it.Stop(ctx); err != nil {
+				t.Fatalf("stop: %v", err)
+			}
+
+			// resume from the position holding the positions of both tables.
+			it = newIterator()
+			if err := it.Setup(ctx, records[len(records)-1].Position); err != nil {
+				t.Fatalf("setup: %v", err)
+			}
+
+			records = append(records, readRecords(ctx, t, it, -1)...)
+
+			got := make([]string, len(records))
+			for i, record := range records {
+				got[i] = name(t, record)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("read = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
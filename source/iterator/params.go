@@ -0,0 +1,41 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import "github.com/conduitio-labs/conduit-connector-firebolt/client"
+
+// Params is incoming params for the iterator constructors.
+type Params struct {
+	Client    *client.Client
+	BatchSize int
+	Table     string
+	// Query - a SELECT query which result is read instead of the table, if it's set.
+	Query string
+	// Filter - a WHERE clause predicate limiting the read rows, if it's set.
+	Filter string
+	// TrackingColumn - name of the column which value increases when a row is inserted or updated.
+	TrackingColumn string
+	// Columns - list of columns to read from the table, all the columns are read if it's empty.
+	Columns []string
+	// OrderingColumns - names of the columns that the iterator uses for ordering rows.
+	OrderingColumns []string
+	// PrimaryKeys - names of the columns that the iterator uses for the record keys.
+	PrimaryKeys []string
+	// RawPayload - the record payloads are rows encoded as JSON instead of structured data.
+	RawPayload bool
+	// SnapshotPartitions - the number of ranges of the first ordering column values,
+	// which the snapshot iterator reads concurrently, the snapshot isn't partitioned if it's less than 2.
+	SnapshotPartitions int
+}
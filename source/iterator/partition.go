@@ -0,0 +1,302 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+	"github.com/conduitio-labs/conduit-connector-firebolt/source/position"
+)
+
+// ErrPartitionColumnNotInteger occurs when the snapshot is partitioned by a column which values are not integers.
+var ErrPartitionColumnNotInteger = errors.New("partition column values must be integers")
+
+// partitionRow is a row read from a partition of the snapshot.
+type partitionRow struct {
+	// partition - the index of the partition the row is read from.
+	partition int
+	row       map[string]any
+	// columns - the columns of the query result the row is read with.
+	columns []client.Column
+}
+
+// partitionReader reads the partitions of the snapshot concurrently and sends their rows through one channel.
+// The rows of each partition are sent in the order of the ordering columns.
+type partitionReader struct {
+	// rows - the rows of all the partitions, it's closed when all the partitions are read or reading failed.
+	rows   chan partitionRow
+	cancel context.CancelFunc
+	// wg - the goroutines reading the partitions.
+	wg sync.WaitGroup
+
+	mu sync.Mutex
+	// err - the first error of reading a partition, the other partitions are stopped after it.
+	err error
+}
+
+// fail stops reading the partitions and records the error if it's the first one.
+func (r *partitionReader) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err == nil {
+		r.err = err
+		r.cancel()
+	}
+}
+
+// stop stops reading the partitions and waits until the goroutines reading them return,
+// so they don't use the client after it's closed.
+func (r *partitionReader) stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// error returns the error of reading the partitions.
+func (r *partitionReader) error() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
+
+// splitPartitions splits the range of the first ordering column values from minValue to maxValue into
+// at most count partitions of equal size. The first and the last partitions are unbounded, so the rows
+// out of the range, e.g. inserted after it's computed, are read as well.
+func splitPartitions(minValue, maxValue any, count int) ([]position.Partition, error) {
+	minRat, minOk := toRat(minValue)
+	maxRat, maxOk := toRat(maxValue)
+
+	if !minOk || !maxOk || !minRat.IsInt() || !maxRat.IsInt() {
+		return nil, fmt.Errorf("range from %v to %v: %w", minValue, maxValue, ErrPartitionColumnNotInteger)
+	}
+
+	minInt, maxInt := minRat.Num(), maxRat.Num()
+
+	// size is the number of values in the range divided by the count and rounded up.
+	size := new(big.Int).Sub(maxInt, minInt)
+	size.Add(size, big.NewInt(int64(count)))
+	size.Quo(size, big.NewInt(int64(count)))
+
+	partitions := make([]position.Partition, 0, count)
+
+	var from any
+
+	for k := 1; k < count; k++ {
+		bound := new(big.Int).Mul(size, big.NewInt(int64(k)))
+		bound.Add(bound, minInt)
+
+		if bound.Cmp(maxInt) > 0 {
+			break
+		}
+
+		to := integerValue(bound)
+		partitions = append(partitions, position.Partition{From: from, To: to})
+		from = to
+	}
+
+	return append(partitions, position.Partition{From: from}), nil
+}
+
+// integerValue returns the value as int64 if it fits, so that it's encoded in the position as a regular number.
+func integerValue(value *big.Int) any {
+	if value.IsInt64() {
+		return value.Int64()
+	}
+
+	return value
+}
+
+// computePartitions splits the snapshot into partitions by the minimum and maximum values of the first
+// ordering column. It returns nil if there are no rows to read.
+func (i *SnapshotIterator) computePartitions(ctx context.Context) ([]position.Partition, error) {
+	minValue, maxValue, err := i.client.GetMinMaxValues(ctx, client.GetMaxValueParams{
+		Table:  i.table,
+		Query:  i.query,
+		Filter: i.filter,
+		Column: i.orderingColumns[0],
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get min and max values of %q: %w", i.orderingColumns[0], err)
+	}
+
+	if minValue == nil || maxValue == nil {
+		return nil, nil
+	}
+
+	return splitPartitions(minValue, maxValue, i.partitionCount)
+}
+
+// startPartitions starts reading the partitions concurrently, each of them from its last processed row.
+func (i *SnapshotIterator) startPartitions(ctx context.Context) {
+	// the partitions are read until the iterator is stopped, not only during the setup.
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	i.reader = &partitionReader{
+		rows:   make(chan partitionRow, i.batchSize),
+		cancel: cancel,
+	}
+
+	for idx, partition := range i.partitions {
+		i.reader.wg.Add(1)
+
+		go func() {
+			defer i.reader.wg.Done()
+
+			if err := i.readPartition(ctx, idx, partition); err != nil {
+				i.reader.fail(fmt.Errorf("read partition %d: %w", idx, err))
+			}
+		}()
+	}
+
+	go func() {
+		i.reader.wg.Wait()
+		close(i.reader.rows)
+	}()
+}
+
+// readPartition reads the rows of the partition in batches and sends them to the reader.
+func (i *SnapshotIterator) readPartition(ctx context.Context, idx int, partition position.Partition) error {
+	after := partition.LastProcessedValues
+
+	for {
+		rows, columns, err := i.client.GetRowsWithColumns(ctx, client.GetRowsParams{
+			Table:           i.table,
+			Query:           i.query,
+			Filter:          i.filter,
+			Columns:         i.columns,
+			OrderingColumns: i.orderingColumns,
+			After:           after,
+			BoundColumn:     i.trackingColumn,
+			BoundValue:      i.trackingBound,
+			RangeColumn:     i.orderingColumns[0],
+			RangeFrom:       partition.From,
+			RangeTo:         partition.To,
+			Limit:           i.batchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("get rows: %w", err)
+		}
+
+		for _, row := range rows {
+			select {
+			case i.reader.rows <- partitionRow{partition: idx, row: row, columns: columns}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if len(rows) < i.batchSize {
+			return nil
+		}
+
+		if after, err = rowValues(rows[len(rows)-1], i.orderingColumns); err != nil {
+			return err
+		}
+	}
+}
+
+// hasNextPartitioned waits for the next row of any partition, it reports false when all the partitions are read.
+func (i *SnapshotIterator) hasNextPartitioned(ctx context.Context) (bool, error) {
+	if i.pending != nil {
+		return true, nil
+	}
+
+	select {
+	case row, ok := <-i.reader.rows:
+		if !ok {
+			return false, i.reader.error()
+		}
+
+		schema, err := i.schemas.register(i.table, row.columns)
+		if err != nil {
+			return false, fmt.Errorf("register schema: %w", err)
+		}
+
+		i.schema = schema
+		i.pending = &row
+
+		return true, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// nextPartitioned returns a record of the pending row, its position holds the progress of all the partitions.
+func (i *SnapshotIterator) nextPartitioned() (sdk.Record, error) {
+	row := i.pending.row
+
+	values, err := rowValues(row, i.orderingColumns)
+	if err != nil {
+		return sdk.Record{}, err
+	}
+
+	payload, err := recordPayload(row, i.rawPayload)
+	if err != nil {
+		return sdk.Record{}, err
+	}
+
+	key, err := recordKey(row, i.primaryKeys)
+	if err != nil {
+		return sdk.Record{}, err
+	}
+
+	lastProcessedValues, err := maxValues(i.lastProcessedValues, values, i.orderingColumns)
+	if err != nil {
+		return sdk.Record{}, err
+	}
+
+	i.partitions[i.pending.partition].LastProcessedValues = values
+
+	p, err := position.NewPartitionedPosition(i.partitions, i.trackingBound, i.filter).ToSDKPosition()
+	if err != nil {
+		return sdk.Record{}, err
+	}
+
+	// the maximum processed values are the last created row known by the CDC iterator after the snapshot.
+	i.lastProcessedValues = lastProcessedValues
+	i.pending = nil
+
+	return sdk.Util.Source.NewRecordSnapshot(p, recordMetadata(i.table, i.schema), key, payload), nil
+}
+
+// maxValues returns the values which follow the other ones in the order of the columns, nil values are ignored.
+func maxValues(a, b map[string]any, columns []string) (map[string]any, error) {
+	if a == nil {
+		return b, nil
+	}
+
+	if b == nil {
+		return a, nil
+	}
+
+	res, err := compareRows(a, b, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	if res < 0 {
+		return b, nil
+	}
+
+	return a, nil
+}
@@ -0,0 +1,198 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client/fireboltest"
+	"github.com/conduitio-labs/conduit-connector-firebolt/source/position"
+)
+
+func TestSplitPartitions(t *testing.T) {
+	tests := []struct {
+		name     string
+		minValue any
+		maxValue any
+		count    int
+		want     []position.Partition
+		wantErr  error
+	}{
+		{
+			name:     "equal ranges",
+			minValue: int64(1),
+			maxValue: int64(100),
+			count:    4,
+			want: []position.Partition{
+				{To: int64(26)},
+				{From: int64(26), To: int64(51)},
+				{From: int64(51), To: int64(76)},
+				{From: int64(76)},
+			},
+		},
+		{
+			name:     "range smaller than the count",
+			minValue: int64(5),
+			maxValue: int64(6),
+			count:    4,
+			want:     []position.Partition{{To: int64(6)}, {From: int64(6)}},
+		},
+		{
+			name:     "single value",
+			minValue: json.Number("7"),
+			maxValue: json.Number("7"),
+			count:    4,
+			want:     []position.Partition{{}},
+		},
+		{
+			name:     "not integer values",
+			minValue: "a",
+			maxValue: "z",
+			count:    2,
+			wantErr:  ErrPartitionColumnNotInteger,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitPartitions(tt.minValue, tt.maxValue, tt.count)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("split error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("split = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPartitionReader_stop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := &partitionReader{rows: make(chan partitionRow), cancel: cancel}
+
+	// the partition is still being read for a while after the context is canceled, e.g. a query is returning.
+	var returned atomic.Bool
+
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		returned.Store(true)
+	}()
+
+	r.stop()
+
+	if !returned.Load() {
+		t.Error("stop returned before the partition was read")
+	}
+}
+
+func TestSnapshotIterator_Partitioned(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	for _, query := range []string{
+		"CREATE DIMENSION TABLE users (id INT, name TEXT)",
+		"INSERT INTO users VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e'), (6, 'f'), (7, 'g'), (8, 'h'), " +
+			"(9, 'i'), (10, 'j'), (11, 'k'), (12, 'l')",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	params := Params{
+		Client:             newTestClient(ctx, t, srv),
+		BatchSize:          2,
+		Table:              testTable,
+		OrderingColumns:    []string{"id"},
+		TrackingColumn:     "id",
+		SnapshotPartitions: 3,
+	}
+
+	it := NewSnapshotIterator(params)
+	if err := it.Setup(ctx, nil); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	// read a part of the rows and stop.
+	var (
+		got  []string
+		last sdk.Record
+	)
+
+	for range 5 {
+		hasNext, err := it.HasNext(ctx)
+		if err != nil || !hasNext {
+			t.Fatalf("has next = %t, %v", hasNext, err)
+		}
+
+		if last, err = it.Next(ctx); err != nil {
+			t.Fatalf("next: %v", err)
+		}
+
+		got = append(got, name(t, last))
+	}
+
+	if err := it.Stop(ctx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	pos, err := position.ParseSDKPosition(last.Position)
+	if err != nil {
+		t.Fatalf("parse position: %v", err)
+	}
+
+	if len(pos.Partitions) != 3 {
+		t.Fatalf("position partitions = %v, want 3 partitions", pos.Partitions)
+	}
+
+	// each partition resumes after its last processed row.
+	params.Client = newTestClient(ctx, t, srv)
+
+	it = NewSnapshotIterator(params)
+	if err = it.Setup(ctx, last.Position); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	got = append(got, readNames(ctx, t, it)...)
+	slices.Sort(got)
+
+	want := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("read = %v, want %v", got, want)
+	}
+
+	// the last processed values are the maximum ones of all the partitions.
+	if it.lastProcessedValues["id"] != int64(12) {
+		t.Errorf("last processed values = %v, want id 12", it.lastProcessedValues)
+	}
+}
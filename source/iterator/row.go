@@ -0,0 +1,308 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+	"github.com/conduitio-labs/conduit-connector-firebolt/source/position"
+)
+
+// rowValues returns the values of the columns from the row.
+func rowValues(row map[string]any, columns []string) (map[string]any, error) {
+	values := make(map[string]any, len(columns))
+
+	for _, col := range columns {
+		val, ok := row[col]
+		if !ok {
+			return nil, fmt.Errorf("row value of %q: %w", col, ErrNoOrderingColumn)
+		}
+
+		values[col] = val
+	}
+
+	return values, nil
+}
+
+// recordKey builds a record key from the values of the primary keys columns.
+func recordKey(row map[string]any, primaryKeys []string) (sdk.StructuredData, error) {
+	keysMap := make(sdk.StructuredData, len(primaryKeys))
+
+	for _, val := range primaryKeys {
+		if _, ok := row[val]; !ok {
+			return nil, fmt.Errorf("key %v, %w", val, ErrNoKey)
+		}
+
+		keysMap[val] = row[val]
+	}
+
+	return keysMap, nil
+}
+
+// recordPayload builds a record payload from the row, it's either structured data
+// with the typed values of the row, or the row encoded as JSON if raw is true.
+func recordPayload(row map[string]any, raw bool) (sdk.Data, error) {
+	if !raw {
+		return sdk.StructuredData(row), nil
+	}
+
+	payload, err := json.Marshal(row)
+	if err != nil {
+		return nil, fmt.Errorf("marshal error : %w", err)
+	}
+
+	return sdk.RawData(payload), nil
+}
+
+// recordMetadata returns metadata of a record read from the table, including the schema of the record.
+// The table is omitted if the record is read from the result of a query.
+func recordMetadata(table string, schema registeredSchema) sdk.Metadata {
+	metadata := sdk.Metadata{}
+	metadata.SetCreatedAt(time.Now())
+
+	if table != "" {
+		metadata[metadataTable] = table
+	}
+
+	if schema.encoded != "" {
+		metadata[metadataSchema] = schema.encoded
+		metadata[metadataSchemaVersion] = schema.version
+	}
+
+	return metadata
+}
+
+// populatePrimaryKeys returns primaryKeys if they are not empty, otherwise the primary keys from the database metadata
+// or the ordering columns if the table doesn't have primary indexes or the rows are read from the result of a query.
+func populatePrimaryKeys(
+	ctx context.Context,
+	cl *client.Client,
+	table, query string,
+	primaryKeys, orderingColumns []string,
+) ([]string, error) {
+	if len(primaryKeys) != 0 {
+		return primaryKeys, nil
+	}
+
+	if query != "" {
+		return orderingColumns, nil
+	}
+
+	primaryKeys, err := cl.GetPrimaryKeys(ctx, table)
+	if err != nil {
+		return nil, fmt.Errorf("get primary keys: %w", err)
+	}
+
+	if len(primaryKeys) != 0 {
+		return primaryKeys, nil
+	}
+
+	return orderingColumns, nil
+}
+
+// validateRows checks that the rows of the table or the query result can be read with the filter by getting them
+// with LIMIT 0, and that the query result contains the ordering and tracking columns, their values are needed
+// to continue reading after the last processed row. It does nothing if neither the query nor the filter is set.
+func validateRows(
+	ctx context.Context,
+	cl *client.Client,
+	params client.GetRowsParams,
+	orderingColumns []string,
+	trackingColumn string,
+) error {
+	if params.Query == "" && params.Filter == "" {
+		return nil
+	}
+
+	params.Limit = 0
+
+	_, columns, err := cl.GetRowsWithColumns(ctx, params)
+	if err != nil {
+		return fmt.Errorf("get rows: %w", err)
+	}
+
+	if params.Query == "" {
+		return nil
+	}
+
+	names := make(map[string]struct{}, len(columns))
+	for _, col := range columns {
+		names[col.Name] = struct{}{}
+	}
+
+	for _, col := range append(slices.Clone(orderingColumns), trackingColumn) {
+		if _, ok := names[col]; col != "" && !ok {
+			return fmt.Errorf("query result column %q: %w", col, ErrNoOrderingColumn)
+		}
+	}
+
+	return nil
+}
+
+// restoreValues converts the values restored from a position in place to the Go types of the columns of the rows,
+// e.g. the timestamps encoded as strings to time.Time, so the rows following the position are read with the same
+// literals as after the rows read by the previous run.
+func restoreValues(
+	ctx context.Context,
+	cl *client.Client,
+	params client.GetRowsParams,
+	values ...map[string]any,
+) error {
+	if !slices.ContainsFunc(values, func(v map[string]any) bool { return len(v) > 0 }) {
+		return nil
+	}
+
+	params.Limit = 0
+
+	_, columns, err := cl.GetRowsWithColumns(ctx, params)
+	if err != nil {
+		return fmt.Errorf("get columns: %w", err)
+	}
+
+	for _, v := range values {
+		if err = client.ConvertValues(v, columns); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkFilter returns an error if the position was created with a different filter,
+// the rows following the position can't be read consistently in that case.
+func checkFilter(pos position.Position, filter string) error {
+	if pos.Filter != filter {
+		return fmt.Errorf("position filter %q, config filter %q: %w", pos.Filter, filter, ErrFilterChanged)
+	}
+
+	return nil
+}
+
+// compareRows compares the values of the columns lexicographically and returns -1, 0 or 1.
+func compareRows(a, b map[string]any, columns []string) (int, error) {
+	for _, col := range columns {
+		res, err := compareValues(a[col], b[col])
+		if err != nil {
+			return 0, fmt.Errorf("compare values of %q: %w", col, err)
+		}
+
+		if res != 0 {
+			return res, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// compareValues compares values of a column read from a row or a position and returns -1, 0 or 1.
+// NULL values are considered less than any other value.
+func compareValues(a, b any) (int, error) {
+	switch {
+	case a == nil && b == nil:
+		return 0, nil
+	case a == nil:
+		return -1, nil
+	case b == nil:
+		return 1, nil
+	}
+
+	// numbers of different types are compared exactly, e.g. int64 values read from a row
+	// with json.Number values restored from a position.
+	if an, ok := toRat(a); ok {
+		if bn, ok := toRat(b); ok {
+			return an.Cmp(bn), nil
+		}
+	}
+
+	switch av := a.(type) {
+	case bool:
+		if bv, ok := b.(bool); ok {
+			return cmp.Compare(boolToInt(av), boolToInt(bv)), nil
+		}
+	case time.Time:
+		if bt, ok := toTime(b); ok {
+			return av.Compare(bt), nil
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv), nil
+		}
+
+		// time values restored from a position are strings.
+		if bt, ok := b.(time.Time); ok {
+			if at, ok := toTime(av); ok {
+				return at.Compare(bt), nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("cannot compare %v (%T) with %v (%T)", a, a, b, b)
+}
+
+// toTime returns the time of a time value or a time encoded as an RFC 3339 string.
+func toTime(val any) (time.Time, bool) {
+	switch v := val.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+
+		return t, err == nil
+	default:
+		return time.Time{}, false
+	}
+}
+
+// toRat returns the exact value of a number, it reports false for the values of other types.
+func toRat(val any) (*big.Rat, bool) {
+	switch v := val.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(v), true
+	case uint64:
+		return new(big.Rat).SetUint64(v), true
+	case *big.Int:
+		return new(big.Rat).SetInt(v), v != nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, false
+		}
+
+		return new(big.Rat).SetFloat64(v), true
+	case json.Number:
+		return new(big.Rat).SetString(v.String())
+	default:
+		return nil, false
+	}
+}
+
+// boolToInt returns 1 for true and 0 for false.
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
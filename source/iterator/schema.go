@@ -0,0 +1,90 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+)
+
+const (
+	// metadataSchema is a metadata key of the record schema encoded as JSON.
+	metadataSchema = "firebolt.schema"
+	// metadataSchemaVersion is a metadata key of the record schema version.
+	metadataSchemaVersion = "firebolt.schema.version"
+)
+
+// recordSchema is the schema of the records read from a table.
+type recordSchema struct {
+	Table   string          `json:"table,omitempty"`
+	Version string          `json:"version"`
+	Columns []client.Column `json:"columns"`
+}
+
+// registeredSchema is a schema of a table encoded for the record metadata.
+type registeredSchema struct {
+	columns []client.Column
+	version string
+	encoded string
+}
+
+// schemaRegistry holds the schemas of the tables, a schema is encoded once per its version.
+type schemaRegistry struct {
+	schemas map[string]registeredSchema
+}
+
+// newSchemaRegistry creates an empty schema registry.
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: make(map[string]registeredSchema)}
+}
+
+// register returns the schema of the table with the columns, a new version is registered if the columns changed.
+func (r *schemaRegistry) register(table string, columns []client.Column) (registeredSchema, error) {
+	if schema, ok := r.schemas[table]; ok && slices.Equal(schema.columns, columns) {
+		return schema, nil
+	}
+
+	version, err := schemaVersion(columns)
+	if err != nil {
+		return registeredSchema{}, err
+	}
+
+	encoded, err := json.Marshal(recordSchema{Table: table, Version: version, Columns: columns})
+	if err != nil {
+		return registeredSchema{}, fmt.Errorf("marshal schema: %w", err)
+	}
+
+	schema := registeredSchema{columns: columns, version: version, encoded: string(encoded)}
+	r.schemas[table] = schema
+
+	return schema, nil
+}
+
+// schemaVersion returns a hash of the columns, so the version of the same columns doesn't change after a restart.
+func schemaVersion(columns []client.Column) (string, error) {
+	encoded, err := json.Marshal(columns)
+	if err != nil {
+		return "", fmt.Errorf("marshal columns: %w", err)
+	}
+
+	sum := sha256.Sum256(encoded)
+
+	return hex.EncodeToString(sum[:8]), nil
+}
@@ -0,0 +1,127 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+	"github.com/conduitio-labs/conduit-connector-firebolt/client/fireboltest"
+)
+
+func TestSchemaRegistry(t *testing.T) {
+	columns := []client.Column{{Name: "id", Type: "Int32"}, {Name: "name", Type: "String", Nullable: true}}
+
+	registry := newSchemaRegistry()
+
+	first, err := registry.register("users", columns)
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	var got recordSchema
+	if err = json.Unmarshal([]byte(first.encoded), &got); err != nil {
+		t.Fatalf("unmarshal schema: %v", err)
+	}
+
+	want := recordSchema{Table: "users", Version: first.version, Columns: columns}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("schema = %+v, want %+v", got, want)
+	}
+
+	// the version of the same columns is the same, even in another registry, e.g. after a restart.
+	same, err := newSchemaRegistry().register("users", slices.Clone(columns))
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	if same.version != first.version {
+		t.Errorf("version of the same columns = %q, want %q", same.version, first.version)
+	}
+
+	changed, err := registry.register("users", append(slices.Clone(columns), client.Column{Name: "age", Type: "Int32"}))
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	if changed.version == first.version {
+		t.Errorf("version of the changed columns = %q, want a new version", changed.version)
+	}
+}
+
+func TestSnapshotIterator_Schema(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	for _, query := range []string{
+		"CREATE DIMENSION TABLE users (id INT, name TEXT NULL)",
+		"INSERT INTO users VALUES (1, 'one'), (2, 'two')",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	it := NewSnapshotIterator(Params{
+		Client: newTestClient(ctx, t, srv), BatchSize: 1, Table: testTable, OrderingColumns: []string{"id"},
+	})
+
+	if err := it.Setup(ctx, nil); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	first, err := it.Next(ctx)
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+
+	var got recordSchema
+	if err = json.Unmarshal([]byte(first.Metadata[metadataSchema]), &got); err != nil {
+		t.Fatalf("unmarshal schema: %v", err)
+	}
+
+	want := recordSchema{
+		Table:   testTable,
+		Version: first.Metadata[metadataSchemaVersion],
+		Columns: []client.Column{{Name: "id", Type: "Int32"}, {Name: "name", Type: "String", Nullable: true}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("schema = %+v, want %+v", got, want)
+	}
+
+	// a new schema version is attached to the records read after the table is altered.
+	if err = srv.Exec(fireboltest.DB, "ALTER TABLE users ADD COLUMN age INT NULL"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = it.HasNext(ctx); err != nil {
+		t.Fatalf("has next: %v", err)
+	}
+
+	second, err := it.Next(ctx)
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+
+	if second.Metadata[metadataSchemaVersion] == first.Metadata[metadataSchemaVersion] {
+		t.Errorf("schema version = %q, want a new version", second.Metadata[metadataSchemaVersion])
+	}
+}
@@ -16,10 +16,8 @@ package iterator
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/conduitio-labs/conduit-connector-firebolt/client"
 	"github.com/conduitio-labs/conduit-connector-firebolt/source/position"
@@ -33,14 +31,21 @@ const (
 
 var (
 	ErrNoKey = errors.New("key doesn't exist")
+	// ErrNoOrderingColumn occurs when a row or a position doesn't contain a value of an ordering or tracking column.
+	ErrNoOrderingColumn = errors.New("ordering column doesn't exist")
+	// ErrFilterChanged occurs when the source resumes from a position created with a different filter.
+	ErrFilterChanged = errors.New("filter has changed")
 )
 
 // SnapshotIterator snapshot iterator.
 type SnapshotIterator struct {
 	// firebolt client.
 	client *client.Client
-	// rowNumber - current number of row which iterator converts to record.
-	rowNumber int
+	// lastProcessedValues - values of the ordering columns of the last processed row.
+	lastProcessedValues map[string]any
+	// offset - number of rows to skip when getting the next batch,
+	// it's used only to resume from a position created by previous versions of the connector.
+	offset int
 	// batchSize size of batch.
 	batchSize int
 	// currentBatch - rows in current batch from table.
@@ -51,66 +56,184 @@ type SnapshotIterator struct {
 	columns []string
 	// table which iterator read.
 	table string
+	// query - a SELECT query which result the iterator reads instead of the table.
+	query string
+	// filter - a WHERE clause predicate limiting the read rows.
+	filter string
 	// orderingColumns name of columns that the connector will use for ordering rows.
 	orderingColumns []string
+	// trackingColumn - name of the column which value increases when a row is inserted or updated,
+	// if it's set the iterator reads only the rows up to the trackingBound.
+	trackingColumn string
+	// trackingBound - the maximum value of the tracking column captured when the snapshot started.
+	trackingBound any
+	// rawPayload - the record payloads are rows encoded as JSON instead of structured data.
+	rawPayload bool
+	// schemas - the registered schemas of the records.
+	schemas *schemaRegistry
+	// schema - the schema of the rows of the current batch.
+	schema registeredSchema
+	// partitionCount - the number of partitions the snapshot is split into when it starts.
+	partitionCount int
+	// partitions - the progress of the partitions, it's nil if the snapshot is not partitioned.
+	partitions []position.Partition
+	// reader - reads the partitions concurrently if the snapshot is partitioned.
+	reader *partitionReader
+	// pending - the row received from the reader, which the next record is built from.
+	pending *partitionRow
 }
 
-func NewSnapshotIterator(
-	client *client.Client,
-	batchSize int,
-	table string,
-	columns, orderingColumns, primaryKeys []string,
-) *SnapshotIterator {
+// NewSnapshotIterator creates a new snapshot iterator.
+// If params.TrackingColumn is empty the iterator reads all the rows, including the ones inserted while it's running.
+func NewSnapshotIterator(params Params) *SnapshotIterator {
 	return &SnapshotIterator{
-		client:          client,
-		batchSize:       batchSize,
-		primaryKeys:     primaryKeys,
-		columns:         columns,
-		table:           table,
-		orderingColumns: orderingColumns,
+		client:          params.Client,
+		batchSize:       params.BatchSize,
+		primaryKeys:     params.PrimaryKeys,
+		columns:         params.Columns,
+		table:           params.Table,
+		query:           params.Query,
+		filter:          params.Filter,
+		orderingColumns: params.OrderingColumns,
+		trackingColumn:  params.TrackingColumn,
+		rawPayload:      params.RawPayload,
+		schemas:         newSchemaRegistry(),
+		partitionCount:  params.SnapshotPartitions,
 	}
 }
 
 // Setup iterator.
+// If the snapshot is partitioned, the partitions are computed when it starts and resumed from the position after that.
 func (i *SnapshotIterator) Setup(ctx context.Context, p sdk.Position) error {
 	if p != nil {
-		pos, err := position.ParseSDKPosition(p)
-		if err != nil {
+		if err := i.restorePosition(ctx, p); err != nil {
 			return err
 		}
+	}
+
+	err := validateRows(ctx, i.client, client.GetRowsParams{Table: i.table, Query: i.query, Filter: i.filter},
+		i.orderingColumns, i.trackingColumn)
+	if err != nil {
+		return fmt.Errorf("validate rows: %w", err)
+	}
 
-		i.rowNumber = pos.RowNumber + 1
+	if i.trackingColumn != "" && i.trackingBound == nil {
+		i.trackingBound, err = i.client.GetMaxValue(ctx, client.GetMaxValueParams{
+			Table:  i.table,
+			Query:  i.query,
+			Filter: i.filter,
+			Column: i.trackingColumn,
+		})
+		if err != nil {
+			return fmt.Errorf("get max value of %q: %w", i.trackingColumn, err)
+		}
 	}
 
-	err := i.populatePrimaryKeys(ctx)
+	i.primaryKeys, err = populatePrimaryKeys(ctx, i.client, i.table, i.query, i.primaryKeys, i.orderingColumns)
 	if err != nil {
 		return fmt.Errorf("populate primary keys: %w", err)
 	}
 
-	rows, err := i.client.GetRows(ctx, i.table, i.orderingColumns, i.columns, i.batchSize, i.rowNumber)
+	if p == nil && i.partitionCount > 1 && (i.trackingColumn == "" || i.trackingBound != nil) {
+		if i.partitions, err = i.computePartitions(ctx); err != nil {
+			return fmt.Errorf("compute partitions: %w", err)
+		}
+	}
+
+	if i.partitions != nil {
+		i.startPartitions(ctx)
+
+		return nil
+	}
+
+	err = i.loadBatch(ctx)
 	if err != nil {
 		sdk.Logger(ctx).Debug().Str("table", i.table).Strs("orderingColumns", i.orderingColumns).
 			Strs("columns", i.columns).Int("batchSize", i.batchSize).
-			Int("rowNumber", i.rowNumber).Msg("get rows parameters")
+			Interface("lastProcessedValues", i.lastProcessedValues).Int("offset", i.offset).
+			Msg("get rows parameters")
 
 		return fmt.Errorf("get rows: %w", err)
 	}
 
-	i.currentBatch = rows
+	return nil
+}
+
+// restorePosition sets the iterator up to continue after the position.
+func (i *SnapshotIterator) restorePosition(ctx context.Context, p sdk.Position) error {
+	pos, err := position.ParseSDKPosition(p)
+	if err != nil {
+		return err
+	}
+
+	if err = checkFilter(pos, i.filter); err != nil {
+		return err
+	}
+
+	i.trackingBound = pos.TrackingBound
+	i.partitions = pos.Partitions
+
+	for _, partition := range i.partitions {
+		if i.lastProcessedValues, err = maxValues(i.lastProcessedValues, partition.LastProcessedValues,
+			i.orderingColumns); err != nil {
+			return fmt.Errorf("last processed values of partitions: %w", err)
+		}
+	}
+
+	switch {
+	case pos.LastProcessedValues != nil:
+		for _, col := range i.orderingColumns {
+			if _, ok := pos.LastProcessedValues[col]; !ok {
+				return fmt.Errorf("position value of %q: %w", col, ErrNoOrderingColumn)
+			}
+		}
+
+		i.lastProcessedValues = pos.LastProcessedValues
+
+	case pos.RowNumber != nil:
+		// the first batch is read by offset, the following ones after the last row of that batch.
+		i.offset = *pos.RowNumber + 1
+	}
+
+	return i.restoreValues(ctx)
+}
+
+// restoreValues converts the values restored from the position to the Go types of the columns.
+func (i *SnapshotIterator) restoreValues(ctx context.Context) error {
+	values := []map[string]any{i.lastProcessedValues}
+	for _, partition := range i.partitions {
+		values = append(values, partition.LastProcessedValues)
+	}
+
+	bound := map[string]any{}
+	if i.trackingColumn != "" && i.trackingBound != nil {
+		bound[i.trackingColumn] = i.trackingBound
+	}
+
+	err := restoreValues(ctx, i.client, client.GetRowsParams{Table: i.table, Query: i.query, Filter: i.filter},
+		append(values, bound)...)
+	if err != nil {
+		return fmt.Errorf("restore values: %w", err)
+	}
+
+	if len(bound) > 0 {
+		i.trackingBound = bound[i.trackingColumn]
+	}
 
 	return nil
 }
 
 // HasNext check ability to get next record.
 func (i *SnapshotIterator) HasNext(ctx context.Context) (bool, error) {
-	var err error
+	if i.reader != nil {
+		return i.hasNextPartitioned(ctx)
+	}
 
 	if len(i.currentBatch) > 0 {
 		return true, nil
 	}
 
-	i.currentBatch, err = i.client.GetRows(ctx, i.table, i.orderingColumns, i.columns, i.batchSize, i.rowNumber)
-	if err != nil {
+	if err := i.loadBatch(ctx); err != nil {
 		return false, err
 	}
 
@@ -119,23 +242,28 @@ func (i *SnapshotIterator) HasNext(ctx context.Context) (bool, error) {
 
 // Next get new record.
 func (i *SnapshotIterator) Next(_ context.Context) (sdk.Record, error) {
-	pos := position.NewPosition(i.rowNumber)
+	if i.reader != nil {
+		return i.nextPartitioned()
+	}
+
+	row := i.currentBatch[0]
 
-	payload, err := json.Marshal(i.currentBatch[0])
+	lastProcessedValues, err := rowValues(row, i.orderingColumns)
 	if err != nil {
-		return sdk.Record{}, fmt.Errorf("marshal error : %w", err)
+		return sdk.Record{}, err
 	}
 
-	keysMap := make(map[string]any)
-	for _, val := range i.primaryKeys {
-		if _, ok := i.currentBatch[0][val]; !ok {
-			return sdk.Record{}, fmt.Errorf("key %v, %w", val, ErrNoKey)
-		}
+	payload, err := recordPayload(row, i.rawPayload)
+	if err != nil {
+		return sdk.Record{}, err
+	}
 
-		keysMap[val] = i.currentBatch[0][val]
+	key, err := recordKey(row, i.primaryKeys)
+	if err != nil {
+		return sdk.Record{}, err
 	}
 
-	p, err := pos.ToSDKPosition()
+	p, err := position.NewPosition(lastProcessedValues, i.trackingBound, i.filter).ToSDKPosition()
 	if err != nil {
 		return sdk.Record{}, err
 	}
@@ -146,24 +274,26 @@ func (i *SnapshotIterator) Next(_ context.Context) (sdk.Record, error) {
 		i.currentBatch = nil
 	}
 
-	i.rowNumber++
-
-	metadata := sdk.Metadata{metadataTable: i.table}
-	metadata.SetCreatedAt(time.Now())
+	i.lastProcessedValues = lastProcessedValues
 
-	record := sdk.Util.Source.NewRecordSnapshot(p, metadata,
-		sdk.StructuredData(keysMap), sdk.RawData(payload))
-
-	return record, nil
+	return sdk.Util.Source.NewRecordSnapshot(p, recordMetadata(i.table, i.schema), key, payload), nil
 }
 
 // Stop shutdown iterator.
 func (i *SnapshotIterator) Stop(ctx context.Context) error {
+	i.stop()
 	i.client.Close(ctx)
 
 	return nil
 }
 
+// stop stops reading the partitions of the snapshot, it doesn't close the client.
+func (i *SnapshotIterator) stop() {
+	if i.reader != nil {
+		i.reader.stop()
+	}
+}
+
 // Ack check if record with position was recorded.
 func (i *SnapshotIterator) Ack(ctx context.Context, rp sdk.Position) error {
 	sdk.Logger(ctx).Debug().Str("position", string(rp)).Msg("got ack")
@@ -171,25 +301,39 @@ func (i *SnapshotIterator) Ack(ctx context.Context, rp sdk.Position) error {
 	return nil
 }
 
-// populatePrimaryKeys populates primaryKeys (if it's empty) from the database metadata
-// or from the orderingColumn configuration field in the described order if it's empty.
-func (i *SnapshotIterator) populatePrimaryKeys(ctx context.Context) error {
-	if len(i.primaryKeys) != 0 {
+// loadBatch gets the batch of rows following the last processed row.
+func (i *SnapshotIterator) loadBatch(ctx context.Context) error {
+	// the table was empty when the snapshot started.
+	if i.trackingColumn != "" && i.trackingBound == nil {
 		return nil
 	}
 
-	var err error
-
-	i.primaryKeys, err = i.client.GetPrimaryKeys(ctx, i.table)
+	rows, columns, err := i.client.GetRowsWithColumns(ctx, client.GetRowsParams{
+		Table:           i.table,
+		Query:           i.query,
+		Filter:          i.filter,
+		Columns:         i.columns,
+		OrderingColumns: i.orderingColumns,
+		After:           i.lastProcessedValues,
+		BoundColumn:     i.trackingColumn,
+		BoundValue:      i.trackingBound,
+		Limit:           i.batchSize,
+		Offset:          i.offset,
+	})
 	if err != nil {
-		return fmt.Errorf("get primary keys: %w", err)
+		return err
 	}
 
-	if len(i.primaryKeys) != 0 {
-		return nil
+	if i.schema, err = i.schemas.register(i.table, columns); err != nil {
+		return fmt.Errorf("register schema: %w", err)
 	}
 
-	i.primaryKeys = i.orderingColumns
+	i.currentBatch = rows
+
+	// the rows are read after the last processed one from now on.
+	if len(rows) > 0 {
+		i.offset = 0
+	}
 
 	return nil
 }
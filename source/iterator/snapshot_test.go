@@ -0,0 +1,462 @@
+// Copyright © 2022 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+
+	"github.com/conduitio-labs/conduit-connector-firebolt/client"
+	"github.com/conduitio-labs/conduit-connector-firebolt/client/fireboltest"
+)
+
+const testTable = "users"
+
+func TestSnapshotIterator_Resume(t *testing.T) {
+	tests := []struct {
+		name     string
+		position sdk.Position
+		want     []string
+	}{
+		{
+			name: "from the beginning",
+			want: []string{"a1", "a2", "b1", "b2"},
+		},
+		{
+			name:     "after the last processed values",
+			position: sdk.Position(`{"lastProcessedValues":{"grp":"a","id":2}}`),
+			want:     []string{"b1", "b2"},
+		},
+		{
+			name:     "after the last processed row number",
+			position: sdk.Position(`{"RowNumber":0}`),
+			want:     []string{"a2", "b1", "b2"},
+		},
+		{
+			name:     "row number past the end",
+			position: sdk.Position(`{"RowNumber":10}`),
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			srv := fireboltest.NewServer()
+			defer srv.Close()
+
+			for _, query := range []string{
+				"CREATE DIMENSION TABLE users (grp TEXT, id INT, name TEXT)",
+				"INSERT INTO users VALUES ('b', 2, 'b2'), ('a', 1, 'a1'), ('b', 1, 'b1'), ('a', 2, 'a2')",
+			} {
+				if err := srv.Exec(fireboltest.DB, query); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			it := NewSnapshotIterator(Params{
+				Client: newTestClient(ctx, t, srv), BatchSize: 1, Table: testTable, OrderingColumns: []string{"grp", "id"},
+			})
+
+			if err := it.Setup(ctx, tt.position); err != nil {
+				t.Fatalf("setup: %v", err)
+			}
+
+			got := readNames(ctx, t, it)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("read = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotIterator_ResumeTimestamp(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	for _, query := range []string{
+		"CREATE DIMENSION TABLE users (created TIMESTAMP, id INT, name TEXT)",
+		"INSERT INTO users VALUES ('2022-01-02 10:00:00.5', 1, 'b1'), ('2022-01-01 10:00:00', 1, 'a1'), " +
+			"('2022-01-02 10:00:00.5', 2, 'b2'), ('2022-01-03 00:00:00', 1, 'c1')",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	params := Params{
+		Client: newTestClient(ctx, t, srv), BatchSize: 1, Table: testTable, OrderingColumns: []string{"created", "id"},
+		TrackingColumn: "created",
+	}
+
+	it := NewSnapshotIterator(params)
+	if err := it.Setup(ctx, nil); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	records := readRecords(ctx, t, it, 2)
+
+	// the iterator is restarted from the position of the second record.
+	params.Client = newTestClient(ctx, t, srv)
+
+	it = NewSnapshotIterator(params)
+	if err := it.Setup(ctx, records[1].Position); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	// the timestamps restored from the position are compared as timestamps, as the ones read from the rows.
+	want := time.Date(2022, 1, 2, 10, 0, 0, 500000000, time.UTC)
+	if got := it.lastProcessedValues["created"]; got != want {
+		t.Errorf("restored value = %v (%T), want %v", got, got, want)
+	}
+
+	if got := it.trackingBound; got != time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("restored tracking bound = %v (%T)", got, got)
+	}
+
+	got := append([]string{name(t, records[0]), name(t, records[1])}, readNames(ctx, t, it)...)
+	if wantNames := []string{"a1", "b1", "b2", "c1"}; !reflect.DeepEqual(got, wantNames) {
+		t.Errorf("read = %v, want %v", got, wantNames)
+	}
+}
+
+func TestSnapshotIterator_RowsInsertedBeforeCursor(t *testing.T) {
+	ctx := context.Background()
+
+	srv := fireboltest.NewServer()
+	defer srv.Close()
+
+	for _, query := range []string{
+		"CREATE DIMENSION TABLE users (id INT, name TEXT)",
+		"INSERT INTO users VALUES (2, 'two'), (4, 'four'), (6, 'six')",
+	} {
+		if err := srv.Exec(fireboltest.DB, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	it := NewSnapshotIterator(Params{
+		Client: newTestClient(ctx, t, srv), BatchSize: 1, Table: testTable, OrderingColumns: []string{"id"},
+	})
+
+	if err := it.Setup(ctx, nil); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	record, err := it.Next(ctx)
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+
+	// a row inserted before the cursor must neither be read nor shift the following rows.
+	if err = srv.Exec(fireboltest.DB, "INSERT INTO users VALUES (1, 'one')"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := append([]string{name(t, record)}, readNames(ctx, t, it)...)
+
+	want := []string{"two", "four", "six"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("read = %v, want %v", got, want)
+	}
+}
+
+func TestSnapshotIterator_PayloadFormat(t *testing.T) {
+	created := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		rawPayload bool
+		want       sdk.Data
+	}{
+		{
+			name: "structured",
+			want: sdk.StructuredData{"id": int64(1), "name": "one", "created": created},
+		},
+		{
+			name:       "raw",
+			rawPayload: true,
+			want:       sdk.RawData(`{"created":"2022-01-02T00:00:00Z","id":1,"name":"one"}`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			srv := fireboltest.NewServer()
+			defer srv.Close()
+
+			for _, query := range []string{
+				"CREATE DIMENSION TABLE users (id INT, name TEXT, created DATE)",
+				"INSERT INTO users VALUES (1, 'one', '2022-01-02')",
+			} {
+				if err := srv.Exec(fireboltest.DB, query); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			it := NewSnapshotIterator(Params{
+				Client:          newTestClient(ctx, t, srv),
+				BatchSize:       1,
+				Table:           testTable,
+				OrderingColumns: []string{"id"},
+				RawPayload:      tt.rawPayload,
+			})
+
+			if err := it.Setup(ctx, nil); err != nil {
+				t.Fatalf("setup: %v", err)
+			}
+
+			record, err := it.Next(ctx)
+			if err != nil {
+				t.Fatalf("next: %v", err)
+			}
+
+			if !reflect.DeepEqual(record.Payload.After, tt.want) {
+				t.Errorf("payload = %#v, want %#v", record.Payload.After, tt.want)
+			}
+
+			// the key is structured data in both formats.
+			wantKey := sdk.StructuredData{"id": int64(1)}
+			if !reflect.DeepEqual(record.Key, wantKey) {
+				t.Errorf("key = %#v, want %#v", record.Key, wantKey)
+			}
+		})
+	}
+}
+
+func TestSnapshotIterator_Query(t *testing.T) {
+	tests := []struct {
+		name            string
+		query           string
+		orderingColumns []string
+		want            []string
+		wantErr         bool
+	}{
+		{
+			name:            "rows of the query result",
+			query:           "SELECT id + 10 AS num, name FROM users WHERE id > 1;",
+			orderingColumns: []string{"num"},
+			want:            []string{"two", "three", "four"},
+		},
+		{
+			name:            "ordering column is missed in the query result",
+			query:           "SELECT name FROM users",
+			orderingColumns: []string{"id"},
+			wantErr:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			srv := fireboltest.NewServer()
+			defer srv.Close()
+
+			for _, query := range []string{
+				"CREATE DIMENSION TABLE users (id INT, name TEXT)",
+				"INSERT INTO users VALUES (3, 'three'), (1, 'one'), (4, 'four'), (2, 'two')",
+			} {
+				if err := srv.Exec(fireboltest.DB, query); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			it := NewSnapshotIterator(Params{
+				Client:          newTestClient(ctx, t, srv),
+				BatchSize:       2,
+				Query:           tt.query,
+				OrderingColumns: tt.orderingColumns,
+				TrackingColumn:  tt.orderingColumns[0],
+			})
+
+			err := it.Setup(ctx, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setup error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			record, err := it.Next(ctx)
+			if err != nil {
+				t.Fatalf("next: %v", err)
+			}
+
+			// the records of a query result are keyed by the ordering columns and not bound to a table.
+			wantKey := sdk.StructuredData{"num": int64(12)}
+			if !reflect.DeepEqual(record.Key, wantKey) {
+				t.Errorf("key = %#v, want %#v", record.Key, wantKey)
+			}
+
+			if table, ok := record.Metadata[metadataTable]; ok {
+				t.Errorf("metadata table = %q, want none", table)
+			}
+
+			got := append([]string{name(t, record)}, readNames(ctx, t, it)...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("read = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotIterator_Filter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   string
+		position sdk.Position
+		want     []string
+		wantErr  bool
+		// wantErrIs is the error the setup error must wrap, if it's set.
+		wantErrIs error
+	}{
+		{
+			name:   "rows matching the filter",
+			filter: "grp = 'a' OR id = 4",
+			want:   []string{"one", "two", "four"},
+		},
+		{
+			name:     "resume with the same filter",
+			filter:   "grp = 'a' OR id = 4",
+			position: sdk.Position(`{"lastProcessedValues":{"id":2},"filter":"grp = 'a' OR id = 4"}`),
+			want:     []string{"four"},
+		},
+		{
+			name:      "resume with a changed filter",
+			filter:    "grp = 'b'",
+			position:  sdk.Position(`{"lastProcessedValues":{"id":2},"filter":"grp = 'a' OR id = 4"}`),
+			wantErr:   true,
+			wantErrIs: ErrFilterChanged,
+		},
+		{
+			name:    "invalid filter",
+			filter:  "unknown = 1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			srv := fireboltest.NewServer()
+			defer srv.Close()
+
+			for _, query := range []string{
+				"CREATE DIMENSION TABLE users (grp TEXT, id INT, name TEXT)",
+				"INSERT INTO users VALUES ('a', 1, 'one'), ('a', 2, 'two'), ('b', 3, 'three'), ('b', 4, 'four')",
+			} {
+				if err := srv.Exec(fireboltest.DB, query); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			it := NewSnapshotIterator(Params{
+				Client:          newTestClient(ctx, t, srv),
+				BatchSize:       1,
+				Table:           testTable,
+				Filter:          tt.filter,
+				OrderingColumns: []string{"id"},
+				TrackingColumn:  "id",
+			})
+
+			err := it.Setup(ctx, tt.position)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setup error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("setup error = %v, want %v", err, tt.wantErrIs)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			got := readNames(ctx, t, it)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("read = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestClient(ctx context.Context, t *testing.T, srv *fireboltest.Server) *client.Client {
+	t.Helper()
+
+	cl := client.New(ctx, srv.URL, fireboltest.DB)
+	t.Cleanup(func() { cl.Close(ctx) })
+
+	err := cl.Login(ctx, client.LoginParams{
+		Email:       fireboltest.Email,
+		Password:    fireboltest.Password,
+		AccountName: fireboltest.AccountName,
+		EngineName:  fireboltest.EngineName,
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	return cl
+}
+
+// readNames reads all the remaining records and returns values of their name column.
+func readNames(ctx context.Context, t *testing.T, it *SnapshotIterator) []string {
+	t.Helper()
+
+	var names []string
+
+	for {
+		hasNext, err := it.HasNext(ctx)
+		if err != nil {
+			t.Fatalf("has next: %v", err)
+		}
+
+		if !hasNext {
+			return names
+		}
+
+		record, err := it.Next(ctx)
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+
+		names = append(names, name(t, record))
+	}
+}
+
+func name(t *testing.T, record sdk.Record) string {
+	t.Helper()
+
+	var payload map[string]any
+	if err := json.Unmarshal(record.Payload.After.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+
+	return payload["name"].(string)
+}
@@ -15,20 +15,116 @@
 package position
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 
 	sdk "github.com/conduitio/conduit-connector-sdk"
 )
 
-// Position represents Snowflake position.
+// ErrNotTablesPosition occurs when a position of a single table is parsed as a position of several tables.
+var ErrNotTablesPosition = errors.New("position doesn't hold positions of tables")
+
+// Mode defines an iterator mode.
+type Mode string
+
+const (
+	// ModeSnapshot is a mode of the snapshot iterator.
+	ModeSnapshot Mode = "snapshot"
+	// ModeCDC is a mode of the CDC iterator.
+	ModeCDC Mode = "cdc"
+)
+
+// Position represents Firebolt position.
 type Position struct {
-	// RowNumber - number of row.
-	RowNumber int
+	// Mode - the iterator mode the position was created in, an empty mode means ModeSnapshot.
+	Mode Mode `json:"mode,omitempty"`
+	// LastProcessedValues - values of the ordering columns of the last processed row,
+	// in ModeCDC they also hold the value of the tracking column.
+	LastProcessedValues map[string]any `json:"lastProcessedValues,omitempty"`
+	// TrackingBound - the maximum value of the tracking column captured when the snapshot started,
+	// the snapshot reads the rows up to it and the CDC iterator continues after it.
+	TrackingBound any `json:"trackingBound,omitempty"`
+	// MaxOrderingValues - values of the ordering columns of the last created row known in ModeCDC.
+	// Rows that follow it in the ordering are considered created, others are considered updated.
+	MaxOrderingValues map[string]any `json:"maxOrderingValues,omitempty"`
+	// RowNumber - number of the last processed row.
+	// It's set only by positions created by previous versions of the connector, which paged using offsets.
+	RowNumber *int `json:"RowNumber,omitempty"`
+	// Filter - the filter of the rows the position was created with,
+	// the rows following the position are different if the filter has changed.
+	Filter string `json:"filter,omitempty"`
+	// Partitions - the progress of the partitions of a partitioned snapshot, which are read concurrently.
+	Partitions []Partition `json:"partitions,omitempty"`
+}
+
+// Partition represents the progress of a partition of the snapshot.
+type Partition struct {
+	// From and To - the range of values of the first ordering column, the partition holds the rows which value
+	// is greater than or equal to From and less than To. A nil bound is not applied.
+	From any `json:"from,omitempty"`
+	To   any `json:"to,omitempty"`
+	// LastProcessedValues - values of the ordering columns of the last processed row of the partition.
+	LastProcessedValues map[string]any `json:"lastProcessedValues,omitempty"`
 }
 
 // NewPosition create position.
-func NewPosition(rowNumber int) *Position {
-	return &Position{RowNumber: rowNumber}
+func NewPosition(lastProcessedValues map[string]any, trackingBound any, filter string) *Position {
+	return &Position{
+		Mode:                ModeSnapshot,
+		LastProcessedValues: lastProcessedValues,
+		TrackingBound:       trackingBound,
+		Filter:              filter,
+	}
+}
+
+// NewPartitionedPosition creates a position of the partitioned snapshot.
+func NewPartitionedPosition(partitions []Partition, trackingBound any, filter string) *Position {
+	return &Position{
+		Mode:          ModeSnapshot,
+		Partitions:    partitions,
+		TrackingBound: trackingBound,
+		Filter:        filter,
+	}
+}
+
+// NewCDCPosition creates a position of the CDC iterator.
+func NewCDCPosition(lastProcessedValues, maxOrderingValues map[string]any, filter string) *Position {
+	return &Position{
+		Mode:                ModeCDC,
+		LastProcessedValues: lastProcessedValues,
+		MaxOrderingValues:   maxOrderingValues,
+		Filter:              filter,
+	}
+}
+
+// TablesPosition represents the positions of the tables read by one source, keyed by the table names.
+type TablesPosition struct {
+	Tables map[string]json.RawMessage `json:"tables"`
+}
+
+// ParseTablesPosition parses SDK position and returns TablesPosition.
+func ParseTablesPosition(p sdk.Position) (TablesPosition, error) {
+	var pos TablesPosition
+
+	if p == nil {
+		return pos, nil
+	}
+
+	if err := json.Unmarshal(p, &pos); err != nil {
+		return pos, err
+	}
+
+	if pos.Tables == nil {
+		return pos, ErrNotTablesPosition
+	}
+
+	return pos, nil
+}
+
+// ToSDKPosition formats and returns sdk.Position.
+func (p TablesPosition) ToSDKPosition() (sdk.Position, error) {
+	return json.Marshal(p)
 }
 
 // ParseSDKPosition parses SDK position and returns Position.
@@ -39,7 +135,11 @@ func ParseSDKPosition(p sdk.Position) (Position, error) {
 		return pos, nil
 	}
 
-	err := json.Unmarshal(p, &pos)
+	// numbers are decoded as json.Number, so the integers out of the float64 precision aren't corrupted.
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&pos)
 	if err != nil {
 		return pos, err
 	}
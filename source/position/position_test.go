@@ -25,11 +25,13 @@ import (
 
 func TestParseSDKPosition(t *testing.T) {
 	pos := Position{
-		RowNumber: 10,
+		LastProcessedValues: map[string]any{"id": json.Number("10"), "name": "test"},
 	}
 
 	poBytes, _ := json.Marshal(pos)
 
+	rowNumber := 10
+
 	wrongPosBytes, _ := json.Marshal(map[string]string{"RowNumber": "test"})
 
 	tests := []struct {
@@ -44,6 +46,19 @@ func TestParseSDKPosition(t *testing.T) {
 			in:   sdk.Position(poBytes),
 			want: pos,
 		},
+		{
+			name: "position with integers out of float64 precision",
+			in:   sdk.Position(`{"lastProcessedValues":{"id":9007199254740993},"trackingBound":9007199254740993}`),
+			want: Position{
+				LastProcessedValues: map[string]any{"id": json.Number("9007199254740993")},
+				TrackingBound:       json.Number("9007199254740993"),
+			},
+		},
+		{
+			name: "row number position",
+			in:   sdk.Position(`{"RowNumber":10}`),
+			want: Position{RowNumber: &rowNumber},
+		},
 		{
 			name:        "invalid struct",
 			in:          sdk.Position(wrongPosBytes),
@@ -86,7 +101,8 @@ func TestParseSDKPosition(t *testing.T) {
 
 func TestCombinePosition(t *testing.T) {
 	original := Position{
-		RowNumber: 10,
+		LastProcessedValues: map[string]any{"id": json.Number("10")},
+		Filter:              "tenant_id = 42",
 	}
 	converted, err := original.ToSDKPosition()
 	if err != nil {
@@ -101,3 +117,65 @@ func TestCombinePosition(t *testing.T) {
 		t.Errorf("parse = %v, want %v", back, original)
 	}
 }
+
+func TestParseTablesPosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      sdk.Position
+		want    TablesPosition
+		wantErr error
+	}{
+		{
+			name: "positions of tables",
+			in:   sdk.Position(`{"tables":{"users":{"lastProcessedValues":{"id":10}}}}`),
+			want: TablesPosition{Tables: map[string]json.RawMessage{
+				"users": json.RawMessage(`{"lastProcessedValues":{"id":10}}`),
+			}},
+		},
+		{
+			name: "nil position",
+			in:   nil,
+			want: TablesPosition{},
+		},
+		{
+			name:    "position of a single table",
+			in:      sdk.Position(`{"lastProcessedValues":{"id":10}}`),
+			wantErr: ErrNotTablesPosition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTablesPosition(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parse error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parse = %v, want %v", got, tt.want)
+			}
+
+			if tt.in == nil {
+				return
+			}
+
+			converted, err := got.ToSDKPosition()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			back, err := ParseTablesPosition(converted)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(back, got) {
+				t.Errorf("parse converted = %v, want %v", back, got)
+			}
+		})
+	}
+}
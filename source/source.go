@@ -52,18 +52,20 @@ func New() sdk.Source {
 func (s *Source) Parameters() map[string]sdk.Parameter {
 	return map[string]sdk.Parameter{
 		config.KeyEmail: {
-			Default: "",
-			Validations: []sdk.Validation{
-				sdk.ValidationRequired{},
-			},
-			Description: "The Firebolt email account.",
+			Default:     "",
+			Description: "The Firebolt email account. Required unless a service account is used.",
 		},
 		config.KeyPassword: {
-			Default: "",
-			Validations: []sdk.Validation{
-				sdk.ValidationRequired{},
-			},
-			Description: "The Firebolt account password.",
+			Default:     "",
+			Description: "The Firebolt account password. Required unless a service account is used.",
+		},
+		config.KeyClientID: {
+			Default:     "",
+			Description: "The Firebolt service account client id. Required unless an email is used.",
+		},
+		config.KeyClientSecret: {
+			Default:     "",
+			Description: "The Firebolt service account client secret. Required unless an email is used.",
 		},
 		config.KeyDB: {
 			Default: "",
@@ -73,25 +75,47 @@ func (s *Source) Parameters() map[string]sdk.Parameter {
 			Description: "The Firebolt database name.",
 		},
 		config.KeyAccountName: {
-			Default: "",
-			Validations: []sdk.Validation{
-				sdk.ValidationRequired{},
-			},
-			Description: "The Firebolt account name.",
+			Default:     "",
+			Description: "The Firebolt account name. Required unless engineURL is set.",
 		},
 		config.KeyEngineName: {
+			Default:     "",
+			Description: "The Firebolt engine name. Required unless engineURL is set.",
+		},
+		config.KeyAPIURL: {
+			Default:     client.DefaultAPIURL,
+			Description: "The Firebolt API base URL.",
+		},
+		config.KeyEngineURL: {
 			Default: "",
-			Validations: []sdk.Validation{
-				sdk.ValidationRequired{},
-			},
-			Description: "The Firebolt engine name.",
+			Description: "The Firebolt engine endpoint. If it's set the connector doesn't look up " +
+				"the account and the engine and doesn't start the engine.",
 		},
 		config.KeyTable: {
+			Default:     "",
+			Description: "The table name. One of table, tables or query must be set.",
+		},
+		config.KeyTables: {
 			Default: "",
-			Validations: []sdk.Validation{
-				sdk.ValidationRequired{},
-			},
-			Description: "The table name.",
+			Description: "Comma separated list of table names, glob patterns, e.g. sales_*, or regular expressions " +
+				"between slashes, e.g. /sales_\\d+/, of the tables read by one source. The settings of a table " +
+				"may be set by the tables.<table>.columns, primaryKeys, orderingColumns and trackingColumn " +
+				"config values. One of table, tables or query must be set.",
+		},
+		config.KeyTableIteration: {
+			Default: config.TableIterationRoundRobin,
+			Description: "The way the tables are read, a record of each table in turn (roundRobin), " +
+				"or all the available records of a table before the next one (sequential).",
+		},
+		config.KeyQuery: {
+			Default: "",
+			Description: "A SELECT query which result is read instead of a table. The result must contain " +
+				"the orderingColumns and the trackingColumn. One of table, tables or query must be set.",
+		},
+		config.KeyFilter: {
+			Default: "",
+			Description: "A WHERE clause predicate limiting the read rows, e.g. tenant_id = 42. " +
+				"The position is bound to the filter, the source doesn't resume if it's changed.",
 		},
 		config.KeyColumns: {
 			Default:     "",
@@ -105,13 +129,26 @@ func (s *Source) Parameters() map[string]sdk.Parameter {
 			Default:     "100",
 			Description: "Size of batch",
 		},
+		config.KeyTrackingColumn: {
+			Default: "",
+			Description: "Name of the column which value increases when a row is inserted or updated. The source " +
+				"captures changes after the snapshot is finished. By default it's the first of orderingColumns.",
+		},
+		config.KeyPayloadFormat: {
+			Default: config.PayloadFormatStructured,
+			Description: "The format of the record payloads, either structured data with typed values (structured), " +
+				"or the rows encoded as JSON (raw).",
+		},
+		config.KeySnapshotPartitions: {
+			Default: "1",
+			Description: "The number of ranges of the first of orderingColumns, which values must be integers, " +
+				"that the snapshot is split into and reads concurrently. The snapshot isn't split by default.",
+		},
 		config.KeyOrderingColumns: {
 			Default: "",
-			Validations: []sdk.Validation{
-				sdk.ValidationRequired{},
-			},
 			Description: "Name of columns that the connector will use for ordering rows. Column must contain unique " +
-				"values and suitable for sorting, otherwise the source won't work correctly.",
+				"values and suitable for sorting, otherwise the source won't work correctly. Required unless " +
+				"each of the tables has its own orderingColumns.",
 		},
 	}
 }
@@ -130,21 +167,21 @@ func (s *Source) Configure(_ context.Context, cfgRaw map[string]string) error {
 
 // Open prepare the plugin to start sending records from the given position.
 func (s *Source) Open(ctx context.Context, rp sdk.Position) error {
-	fireboltClient := client.New(ctx, s.config.DB)
+	fireboltClient := client.New(ctx, s.config.APIURL, s.config.DB)
 
 	err := fireboltClient.Login(ctx, client.LoginParams{
-		Email:       s.config.Email,
-		Password:    s.config.Password,
-		AccountName: s.config.AccountName,
-		EngineName:  s.config.EngineName,
+		Email:        s.config.Email,
+		Password:     s.config.Password,
+		ClientID:     s.config.ClientID,
+		ClientSecret: s.config.ClientSecret,
+		AccountName:  s.config.AccountName,
+		EngineName:   s.config.EngineName,
+		EngineURL:    s.config.EngineURL,
 	})
 	if err != nil {
 		return fmt.Errorf("client login: %w", err)
 	}
 
-	s.iterator = iterator.NewSnapshotIterator(fireboltClient, s.config.BatchSize, s.config.Table, s.config.Columns,
-		s.config.OrderingColumns, s.config.PrimaryKeys)
-
 	ctxWithTimeOut, cancel := context.WithTimeout(ctx, 10*time.Minute)
 	defer cancel()
 
@@ -152,6 +189,27 @@ func (s *Source) Open(ctx context.Context, rp sdk.Position) error {
 		return fmt.Errorf("wait engine started: %w", err)
 	}
 
+	params := s.iteratorParams(fireboltClient)
+
+	if params.Table != "" {
+		if params.Table, err = fireboltClient.ResolveTable(ctx, params.Table); err != nil {
+			return fmt.Errorf("resolve table: %w", err)
+		}
+
+		if err = resolveColumns(ctx, fireboltClient, &params); err != nil {
+			return err
+		}
+	}
+
+	if len(s.config.Tables) == 0 {
+		s.iterator = iterator.NewCombinedIterator(params)
+	} else {
+		s.iterator, err = s.newMultiTableIterator(ctx, fireboltClient, params)
+		if err != nil {
+			return err
+		}
+	}
+
 	if err = s.iterator.Setup(ctx, rp); err != nil {
 		return fmt.Errorf("iterator setup: %w", err)
 	}
@@ -159,6 +217,67 @@ func (s *Source) Open(ctx context.Context, rp sdk.Position) error {
 	return nil
 }
 
+// iteratorParams returns the iterator params of the configured table or query.
+func (s *Source) iteratorParams(fireboltClient *client.Client) iterator.Params {
+	return iterator.Params{
+		Client:             fireboltClient,
+		BatchSize:          s.config.BatchSize,
+		Table:              s.config.Table,
+		Query:              s.config.Query,
+		Filter:             s.config.Filter,
+		TrackingColumn:     s.config.TrackingColumn,
+		Columns:            s.config.Columns,
+		OrderingColumns:    s.config.OrderingColumns,
+		PrimaryKeys:        s.config.PrimaryKeys,
+		RawPayload:         s.config.PayloadFormat == config.PayloadFormatRaw,
+		SnapshotPartitions: s.config.SnapshotPartitions,
+	}
+}
+
+// newMultiTableIterator creates an iterator of the tables matching the configured tables,
+// the iterators of the tables share the client and the params except the table settings.
+func (s *Source) newMultiTableIterator(
+	ctx context.Context, fireboltClient *client.Client, params iterator.Params,
+) (*iterator.MultiTableIterator, error) {
+	existing, err := fireboltClient.ListTables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+
+	tables, err := config.MatchTables(s.config.Tables, existing)
+	if err != nil {
+		return nil, fmt.Errorf("match tables: %w", err)
+	}
+
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no tables match %q", s.config.Tables)
+	}
+
+	tableParams := make([]iterator.Params, len(tables))
+
+	for i, table := range tables {
+		tableSource, err := s.config.TableSource(table)
+		if err != nil {
+			return nil, err
+		}
+
+		tableParams[i] = params
+		tableParams[i].Table = table
+		tableParams[i].Columns = tableSource.Columns
+		tableParams[i].PrimaryKeys = tableSource.PrimaryKeys
+		tableParams[i].OrderingColumns = tableSource.OrderingColumns
+		tableParams[i].TrackingColumn = tableSource.TrackingColumn
+
+		if err = resolveColumns(ctx, fireboltClient, &tableParams[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	sequential := s.config.TableIteration == config.TableIterationSequential
+
+	return iterator.NewMultiTableIterator(tableParams, sequential), nil
+}
+
 // Read gets the next object from the firebolt.
 func (s *Source) Read(ctx context.Context) (sdk.Record, error) {
 	hasNext, err := s.iterator.HasNext(ctx)
@@ -199,3 +318,36 @@ func (s *Source) Ack(ctx context.Context, p sdk.Position) error {
 func (s *Source) SetIterator(it Iterator) {
 	s.iterator = it
 }
+
+// resolveColumns replaces the configured columns of the params with the names of the columns in the table,
+// so the columns of the unquoted names can be configured in any case.
+func resolveColumns(ctx context.Context, fireboltClient *client.Client, params *iterator.Params) error {
+	var trackingColumn []string
+	if params.TrackingColumn != "" {
+		trackingColumn = []string{params.TrackingColumn}
+	}
+
+	fields := []*[]string{&params.Columns, &params.PrimaryKeys, &params.OrderingColumns, &trackingColumn}
+
+	var columns []string
+	for _, field := range fields {
+		columns = append(columns, *field...)
+	}
+
+	resolved, err := fireboltClient.ResolveColumns(ctx, params.Table, columns)
+	if err != nil {
+		return fmt.Errorf("resolve columns: %w", err)
+	}
+
+	for _, field := range fields {
+		if n := len(*field); n > 0 {
+			*field, resolved = resolved[:n:n], resolved[n:]
+		}
+	}
+
+	if len(trackingColumn) > 0 {
+		params.TrackingColumn = trackingColumn[0]
+	}
+
+	return nil
+}
@@ -366,13 +366,14 @@ func prepareConfig() (map[string]string, error) {
 }
 
 func login(ctx context.Context, cfg map[string]string) (*client.Client, error) {
-	cl := client.New(ctx, cfg[config.KeyDB])
+	cl := client.New(ctx, cfg[config.KeyAPIURL], cfg[config.KeyDB])
 
 	err := cl.Login(ctx, client.LoginParams{
 		Email:       cfg[config.KeyEmail],
 		Password:    cfg[config.KeyPassword],
 		AccountName: cfg[config.KeyAccountName],
 		EngineName:  cfg[config.KeyEngineName],
+		EngineURL:   cfg[config.KeyEngineURL],
 	})
 	if err != nil {
 		return nil, fmt.Errorf("client login: %w", err)